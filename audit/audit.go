@@ -0,0 +1,107 @@
+// Package audit keeps an append-only record of everything that happened to game sessions, for resolving disputes about them.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Types of the entries recorded for a session.
+const (
+	EntryCreated      = "created"
+	EntryMove         = "move"
+	EntryMoveRejected = "move_rejected"
+	EntryResult       = "result"
+	EntryExpired      = "expired"
+	EntryCancelled    = "cancelled"
+	EntryReplayed     = "replayed"
+)
+
+// Entry defines something that happened to a session.
+type Entry struct {
+	Timestamp      time.Time `json:"timestamp"`
+	Type           string    `json:"type"`
+	Session        string    `json:"session"`
+	Actor          string    `json:"actor,omitempty"` // The player responsible for the entry, if any.
+	Challenger     string    `json:"challenger,omitempty"`
+	Target         string    `json:"target,omitempty"`
+	Mode           string    `json:"mode,omitempty"`
+	Move           string    `json:"move,omitempty"` // The move submitted by the Actor, including rejected ones.
+	ChallengerMove string    `json:"challenger_move,omitempty"`
+	TargetMove     string    `json:"target_move,omitempty"`
+	Result         string    `json:"result,omitempty"`
+	Error          string    `json:"error,omitempty"` // Why a move was rejected.
+}
+
+// Reader looks up the entries recorded for a session.
+type Reader interface {
+	History(session string) ([]Entry, error)
+}
+
+// FileLog writes entries to a JSON-lines file, which is only ever appended to.
+type FileLog struct {
+	path  string
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// OpenFile opens the JSON-lines file at path for appending entries, creating it when it doesn't exist.
+func OpenFile(path string) (*FileLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileLog{path: path, file: file}, nil
+}
+
+// Record appends the entry to the file, timestamping it when it has no Timestamp.
+func (fileLog *FileLog) Record(entry Entry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	fileLog.mutex.Lock()
+	defer fileLog.mutex.Unlock()
+
+	_, err = fileLog.file.Write(append(line, '\n'))
+	return err
+}
+
+// History returns the entries recorded for the session, oldest first.
+func (fileLog *FileLog) History(session string) ([]Entry, error) {
+	var entries []Entry
+
+	file, err := os.Open(fileLog.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+
+		if entry.Session == session {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+// Close closes the file.
+func (fileLog *FileLog) Close() error {
+	return fileLog.file.Close()
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileLogHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	fileLog, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fileLog.Close()
+
+	fileLog.Record(Entry{Type: EntryCreated, Session: "one", Challenger: "U1", Target: "U2"})
+	fileLog.Record(Entry{Type: EntryCreated, Session: "two"})
+	fileLog.Record(Entry{Type: EntryMove, Session: "one", Actor: "U1", Move: "rock"})
+
+	entries, err := fileLog.History("one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Type != EntryCreated || entries[1].Move != "rock" || entries[1].Timestamp.IsZero() {
+		t.Fatalf("Expected the session's entries in order, got %+v", entries)
+	}
+}
+
+func TestFileLogAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	for _, session := range []string{"one", "two"} {
+		fileLog, err := OpenFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileLog.Record(Entry{Type: EntryCreated, Session: session})
+		fileLog.Close()
+	}
+
+	data, _ := ioutil.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Fatalf("Expected reopening the file to keep the previous entries, got %q", data)
+	}
+}
@@ -0,0 +1,370 @@
+// Package chat provides the platform independent flow for playing RPS through a chat platform.
+// Each platform implements an Adapter, while the Service takes care of sessions and game resolution.
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/logging"
+	"github.com/hamologist/rps/server"
+)
+
+const (
+	platformKey       = "platform"
+	channelKey        = "channelName"
+	challengerNameKey = "challengerName"
+	targetNameKey     = "targetName"
+	bestOfKey         = "bestOf"
+	challengerWinsKey = "challengerWins"
+	targetWinsKey     = "targetWins"
+	workspaceKey      = "workspace"
+)
+
+// ErrPromptUnsupported is returned by an Adapter's SendPrivatePrompt when the platform has no way of
+// messaging a single player, in which case the prompts need to be part of the rendered challenge.
+var ErrPromptUnsupported = errors.New("The chat platform does not support private prompts")
+
+// ErrMatchesUnsupported is returned when a match is started through an Adapter without private prompts.
+// The following rounds of a match aren't issued through a command, so their prompts couldn't be rendered.
+var ErrMatchesUnsupported = errors.New("Matches of more than one game are not supported on this platform")
+
+// ErrUnauthorized is returned by an Adapter's ParseCommand when the request could not be verified as coming from the platform.
+var ErrUnauthorized = errors.New("The request could not be verified")
+
+// Player defines a chat platform user.
+type Player struct {
+	ID   string
+	Name string
+}
+
+// Command defines a challenge issued through a chat platform.
+type Command struct {
+	Challenger Player
+	Target     Player
+	Channel    string
+	Workspace  string // The platform's workspace (or team) the challenge was issued in, if the platform has any.
+	Mode       string // The name of one of the modes.RegisteredGames, empty for the GameServer's game.
+	BestOf     int    // The number of games in the match, matches of more than one game are replayed until a player wins the majority.
+}
+
+// Challenge defines a challenge once its session has been created.
+type Challenge struct {
+	Command
+	SessionID string
+	Moves     []string // The game's moves, in the game's PreferredOrder.
+}
+
+// Adapter is implemented by every chat platform supported by the Service.
+// Errors returned by ParseCommand are shown to the user, so they should be worded for them.
+type Adapter interface {
+	// Platform returns the name of the chat platform, stored with every session the platform creates.
+	Platform() string
+	// ParseCommand verifies the request was sent by the platform and parses the challenge it carries.
+	ParseCommand(r *http.Request) (Command, error)
+	// RenderChallenge writes the response to the command that issued the challenge.
+	RenderChallenge(w http.ResponseWriter, challenge Challenge) error
+	// SendPrivatePrompt asks a single player to pick their move, without revealing it to anyone else.
+	SendPrivatePrompt(challenge Challenge, player Player) error
+	// PostResult announces a completed session to the channel the challenge was issued in.
+	PostResult(session server.GameSession) error
+}
+
+// ErrorRenderer can be implemented by an Adapter whose platform expects errors in a specific format.
+// Adapters that don't implement it have their errors written as plain text.
+type ErrorRenderer interface {
+	RenderError(w http.ResponseWriter, message string)
+}
+
+// UserError can be implemented by errors returned from an Adapter, to explain the failure to the user.
+type UserError interface {
+	error
+	UserMessage() string
+}
+
+// ErrorMessage returns the message shown to a user for err, or fallback when err doesn't implement UserError.
+func ErrorMessage(err error, fallback string) string {
+	if userError, ok := err.(UserError); ok {
+		return userError.UserMessage()
+	}
+
+	return fallback
+}
+
+const (
+	// resultWorkers bounds how many results are posted through the Adapter at once.
+	resultWorkers = 4
+	// resultQueueSize bounds how many results can wait to be posted, further results are dropped.
+	resultQueueSize = 64
+)
+
+// Service runs challenges for an Adapter on top of a GameServer.
+type Service struct {
+	*server.GameServer
+	Adapter Adapter
+	results chan server.Event
+}
+
+// NewService creates a Service.
+// Results of the platform's sessions are posted using the Adapter, regardless of which front end the final move came from.
+func NewService(gameServer *server.GameServer, adapter Adapter) *Service {
+	service := &Service{
+		GameServer: gameServer,
+		Adapter:    adapter,
+		results:    make(chan server.Event, resultQueueSize),
+	}
+	for i := 0; i < resultWorkers; i++ {
+		go service.postResults()
+	}
+	gameServer.GameSessionsManager.Events.Listen(service.handleEvent)
+
+	return service
+}
+
+// HandleCommand handles a request issuing a challenge through the Adapter's platform.
+func (service *Service) HandleCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		return
+	}
+
+	command, err := service.Adapter.ParseCommand(r)
+	if err == ErrUnauthorized {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		service.renderError(w, err.Error())
+		return
+	}
+
+	ctx := logging.With(r.Context(),
+		slog.String(logging.TeamKey, command.Workspace),
+		slog.String(logging.UserKey, command.Challenger.ID),
+	)
+
+	challenge, err := service.StartChallenge(command)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to issue the challenge", logging.SessionKey, challenge.SessionID, "error", err)
+		service.renderError(w, ErrorMessage(err, "There was a problem issuing the challenge. Please try again."))
+		return
+	}
+
+	if err := service.Adapter.RenderChallenge(w, challenge); err != nil {
+		slog.ErrorContext(ctx, "Failed to render the challenge", logging.SessionKey, challenge.SessionID, "error", err)
+		service.renderError(w, "An error occurred while setting up the game.")
+	}
+}
+
+// StartChallenge creates a session for the command and prompts both players for their move.
+func (service *Service) StartChallenge(command Command) (Challenge, error) {
+	return service.startRound(command, CreateSessionData(service.Adapter.Platform(), command))
+}
+
+func (service *Service) startRound(command Command, data map[string]string) (Challenge, error) {
+	id, err := service.GameSessionsManager.CreateModeSession(command.Challenger.ID, command.Target.ID, command.Mode, data)
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	v, _ := service.GameSessionsManager.Session(id)
+	challenge := Challenge{
+		Command:   command,
+		SessionID: id,
+		Moves:     service.SessionGame(v).PreferredOrder,
+	}
+
+	for _, player := range []Player{command.Target, command.Challenger} {
+		err := service.Adapter.SendPrivatePrompt(challenge, player)
+		if err == ErrPromptUnsupported && command.BestOf <= 1 {
+			continue
+		}
+		if err != nil {
+			// The session can't be played without its prompts, so it isn't left pending until it expires.
+			service.GameSessionsManager.CancelSession(id, "")
+			if err == ErrPromptUnsupported {
+				err = ErrMatchesUnsupported
+			}
+			return challenge, err
+		}
+	}
+
+	return challenge, nil
+}
+
+// nextRound starts the next game of a match, carrying over the score.
+func (service *Service) nextRound(session server.GameSession) {
+	command, _ := SessionCommand(service.Adapter.Platform(), session)
+	challengerWins, targetWins := MatchScore(session)
+
+	data := CreateSessionData(service.Adapter.Platform(), command)
+	data[challengerWinsKey] = strconv.Itoa(challengerWins)
+	data[targetWinsKey] = strconv.Itoa(targetWins)
+
+	if _, err := service.startRound(command, data); err != nil {
+		slog.Error("Failed to start the next round of the match", logging.SessionKey, session.ID, "error", err)
+	}
+}
+
+func (service *Service) renderError(w http.ResponseWriter, message string) {
+	if renderer, ok := service.Adapter.(ErrorRenderer); ok {
+		renderer.RenderError(w, message)
+		return
+	}
+
+	fmt.Fprint(w, message)
+}
+
+// handleEvent queues the result of any session created through the Adapter's platform for postResults.
+// Listeners run under the session lock, so results are dropped rather than waited on once the queue is full.
+func (service *Service) handleEvent(event server.Event) {
+	if event.Type != server.EventResult && event.Type != server.EventReplayed {
+		return
+	}
+	if event.Session.Data[platformKey] != service.Adapter.Platform() {
+		return
+	}
+
+	select {
+	case service.results <- event:
+	default:
+		slog.Error("Too many results are waiting to be posted, dropped the result", logging.SessionKey, event.Session.ID)
+	}
+}
+
+// postResults posts the queued results, starting the next round of unfinished matches.
+// Replayed results are posted again without starting the next round of a match.
+func (service *Service) postResults() {
+	for event := range service.results {
+		if err := service.Adapter.PostResult(event.Session); err != nil {
+			slog.Error("Failed to post the result", logging.SessionKey, event.Session.ID, "error", err)
+		}
+
+		if event.Type == server.EventResult && !MatchOver(event.Session) {
+			service.nextRound(event.Session)
+		}
+	}
+}
+
+// CreateSessionData returns the session Data stored for challenges issued through a chat platform.
+func CreateSessionData(platform string, command Command) map[string]string {
+	data := map[string]string{
+		platformKey:       platform,
+		channelKey:        command.Channel,
+		challengerNameKey: command.Challenger.Name,
+		targetNameKey:     command.Target.Name,
+	}
+
+	if command.Workspace != "" {
+		data[workspaceKey] = command.Workspace
+	}
+	if command.BestOf > 1 {
+		data[bestOfKey] = strconv.Itoa(command.BestOf)
+	}
+
+	return data
+}
+
+// SessionCommand returns the Command a session was created from, if it was created through the platform.
+func SessionCommand(platform string, session server.GameSession) (Command, bool) {
+	data := session.Data
+	if data[platformKey] != platform {
+		return Command{}, false
+	}
+
+	bestOf, err := strconv.Atoi(data[bestOfKey])
+	if err != nil || bestOf < 1 {
+		bestOf = 1
+	}
+
+	return Command{
+		Challenger: Player{ID: session.Challenger, Name: data[challengerNameKey]},
+		Target:     Player{ID: session.Target, Name: data[targetNameKey]},
+		Channel:    data[channelKey],
+		Workspace:  data[workspaceKey],
+		Mode:       session.Mode,
+		BestOf:     bestOf,
+	}, true
+}
+
+// ParseMode returns the registered game mode named by the token, the error is worded for users.
+func ParseMode(token string) (string, error) {
+	mode := strings.ToLower(token)
+	if _, ok := modes.RegisteredGames[mode]; ok {
+		return mode, nil
+	}
+
+	var names []string
+	for name := range modes.RegisteredGames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return "", fmt.Errorf("Unknown game mode \"%v\". The available modes are: %v.", token, strings.Join(names, ", "))
+}
+
+// MatchScore returns the number of games each player has won in the session's match, including the session itself.
+func MatchScore(session server.GameSession) (challengerWins, targetWins int) {
+	challengerWins, _ = strconv.Atoi(session.Data[challengerWinsKey])
+	targetWins, _ = strconv.Atoi(session.Data[targetWinsKey])
+
+	switch session.Result {
+	case game.GameStatePlayerOneWins:
+		challengerWins++
+	case game.GameStatePlayerTwoWins:
+		targetWins++
+	}
+
+	return challengerWins, targetWins
+}
+
+// MatchOver reports whether a player has won the majority of the session's match.
+// Single game challenges are always over, even when they were a draw.
+func MatchOver(session server.GameSession) bool {
+	command, _ := SessionCommand(session.Data[platformKey], session)
+	if command.BestOf <= 1 {
+		return true
+	}
+
+	challengerWins, targetWins := MatchScore(session)
+
+	return challengerWins > command.BestOf/2 || targetWins > command.BestOf/2
+}
+
+// FormatResult describes a completed session, mention is used for formatting each player for the platform.
+func FormatResult(session server.GameSession, mention func(Player) string) string {
+	var result string
+	command, _ := SessionCommand(session.Data[platformKey], session)
+	challenger := mention(command.Challenger)
+	target := mention(command.Target)
+
+	switch session.Result {
+	case game.GameStatePlayerOneWins:
+		result = fmt.Sprintf("%v defeated %v, %v beats %v", challenger, target, session.ChallengerMove, session.TargetMove)
+	case game.GameStatePlayerTwoWins:
+		result = fmt.Sprintf("%v defeated %v, %v beats %v", target, challenger, session.TargetMove, session.ChallengerMove)
+	default:
+		result = fmt.Sprintf("%v and %v had a draw. Both played %v", challenger, target, session.TargetMove)
+	}
+
+	if command.BestOf <= 1 {
+		return result
+	}
+
+	challengerWins, targetWins := MatchScore(session)
+	result += fmt.Sprintf("\nBest of %v: %v %v - %v %v", command.BestOf, challenger, challengerWins, targetWins, target)
+
+	if challengerWins > command.BestOf/2 {
+		result += fmt.Sprintf("\n%v wins the match!", challenger)
+	} else if targetWins > command.BestOf/2 {
+		result += fmt.Sprintf("\n%v wins the match!", target)
+	}
+
+	return result
+}
@@ -0,0 +1,294 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+var mockCommand = Command{
+	Challenger: Player{ID: "U1", Name: "alice"},
+	Target:     Player{ID: "U2", Name: "bob"},
+	Channel:    "general",
+}
+
+type prompt struct {
+	Challenge
+	player Player
+}
+
+type mockAdapter struct {
+	parseErr  error
+	promptErr error
+	command   Command
+	prompts   chan prompt
+	results   chan server.GameSession
+}
+
+func newMockAdapter(command Command) *mockAdapter {
+	return &mockAdapter{
+		command: command,
+		prompts: make(chan prompt, 16),
+		results: make(chan server.GameSession, 16),
+	}
+}
+
+func (adapter *mockAdapter) Platform() string {
+	return "mock"
+}
+
+func (adapter *mockAdapter) ParseCommand(r *http.Request) (Command, error) {
+	return adapter.command, adapter.parseErr
+}
+
+func (adapter *mockAdapter) RenderChallenge(w http.ResponseWriter, challenge Challenge) error {
+	fmt.Fprint(w, challenge.SessionID)
+	return nil
+}
+
+func (adapter *mockAdapter) SendPrivatePrompt(challenge Challenge, player Player) error {
+	if adapter.promptErr != nil {
+		return adapter.promptErr
+	}
+
+	adapter.prompts <- prompt{challenge, player}
+	return nil
+}
+
+func (adapter *mockAdapter) PostResult(session server.GameSession) error {
+	adapter.results <- session
+	return nil
+}
+
+func TestServicePlaysAGame(t *testing.T) {
+	adapter := newMockAdapter(mockCommand)
+	service := NewService(server.NewGameServer(modes.StandardGame), adapter)
+
+	w := httptest.NewRecorder()
+	service.HandleCommand(w, httptest.NewRequest("POST", "/", nil))
+	id := w.Body.String()
+
+	if receivePrompt(t, adapter).player.ID != "U2" || receivePrompt(t, adapter).player.ID != "U1" {
+		t.Fatal("Expected both players to be prompted, starting with the target")
+	}
+
+	service.SubmitMove(id, "U1", "paper")
+	service.SubmitMove(id, "U2", "rock")
+
+	result := FormatResult(receiveResult(t, adapter), mention)
+	if result != "@alice defeated @bob, paper beats rock" {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+}
+
+func TestServicePlaysMatches(t *testing.T) {
+	command := mockCommand
+	command.BestOf = 3
+	adapter := newMockAdapter(command)
+	service := NewService(server.NewGameServer(modes.StandardGame), adapter)
+
+	if _, err := service.StartChallenge(command); err != nil {
+		t.Fatalf("Failed to start the challenge: %q", err)
+	}
+
+	rounds := [][2]string{{"rock", "scissors"}, {"rock", "rock"}, {"paper", "scissors"}, {"paper", "rock"}}
+	for i, moves := range rounds {
+		id := receivePrompt(t, adapter).SessionID
+		receivePrompt(t, adapter)
+
+		service.SubmitMove(id, "U1", moves[0])
+		service.SubmitMove(id, "U2", moves[1])
+
+		session := receiveResult(t, adapter)
+		if MatchOver(session) != (i == len(rounds)-1) {
+			t.Fatalf("Unexpected match state after round %v: %v", i+1, FormatResult(session, mention))
+		}
+	}
+
+	select {
+	case prompt := <-adapter.prompts:
+		t.Fatalf("No rounds should be started once the match is over, %v was prompted", prompt.player)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServicePostsReplayedResults(t *testing.T) {
+	command := mockCommand
+	command.BestOf = 3
+	adapter := newMockAdapter(command)
+	service := NewService(server.NewGameServer(modes.StandardGame), adapter)
+
+	service.StartChallenge(command)
+	id := receivePrompt(t, adapter).SessionID
+	receivePrompt(t, adapter)
+
+	service.SubmitMove(id, "U1", "rock")
+	service.SubmitMove(id, "U2", "scissors")
+	receiveResult(t, adapter)
+	receivePrompt(t, adapter)
+	receivePrompt(t, adapter)
+
+	if _, err := service.GameSessionsManager.ReplayResult(id, server.AdminActor); err != nil {
+		t.Fatalf("Failed to replay the result: %q", err)
+	}
+	if session := receiveResult(t, adapter); session.ID != id {
+		t.Fatalf("Expected the result of %v to be posted again, got %v", id, session.ID)
+	}
+
+	select {
+	case prompt := <-adapter.prompts:
+		t.Fatalf("Replays shouldn't start another round, %v was prompted", prompt.player)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServiceRejectsUnauthorizedCommands(t *testing.T) {
+	gameServer := server.NewGameServer(modes.StandardGame)
+	adapter := newMockAdapter(mockCommand)
+	adapter.parseErr = ErrUnauthorized
+	service := NewService(gameServer, adapter)
+
+	w := httptest.NewRecorder()
+	service.HandleCommand(w, httptest.NewRequest("POST", "/", nil))
+
+	if w.Code != http.StatusUnauthorized || len(gameServer.GameSessionsManager.GameSessions) != 0 {
+		t.Fatalf("Expected status %v, got %v", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestServiceShowsCommandErrors(t *testing.T) {
+	adapter := newMockAdapter(mockCommand)
+	adapter.parseErr = errors.New("Invalid command")
+	service := NewService(server.NewGameServer(modes.StandardGame), adapter)
+
+	w := httptest.NewRecorder()
+	service.HandleCommand(w, httptest.NewRequest("POST", "/", nil))
+
+	if w.Body.String() != "Invalid command" {
+		t.Fatalf("Expected the error to be shown, got %q", w.Body.String())
+	}
+}
+
+func TestFormatResultIncludesMatchScore(t *testing.T) {
+	command := mockCommand
+	command.BestOf = 3
+	session := server.GameSession{
+		Challenger:     "U1",
+		Target:         "U2",
+		ChallengerMove: "rock",
+		TargetMove:     "paper",
+		Result:         game.GameStatePlayerTwoWins,
+		Data:           CreateSessionData("mock", command),
+	}
+	session.Data[targetWinsKey] = "1"
+
+	expected := "@bob defeated @alice, paper beats rock\nBest of 3: @alice 0 - 2 @bob\n@bob wins the match!"
+	if result := FormatResult(session, mention); result != expected {
+		t.Fatalf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if mode, err := ParseMode("RPSLS"); err != nil || mode != "rpsls" {
+		t.Fatalf("Expected the rpsls mode, got %q: %q", mode, err)
+	}
+
+	if _, err := ParseMode("unknown"); err == nil || !strings.Contains(err.Error(), "standard") {
+		t.Fatalf("Expected an error listing the available modes, got %q", err)
+	}
+}
+
+func TestServiceStartsSessionsWithTheCommandsMode(t *testing.T) {
+	command := mockCommand
+	command.Mode = "rpsls"
+	service := NewService(server.NewGameServer(modes.StandardGame), newMockAdapter(command))
+
+	challenge, err := service.StartChallenge(command)
+	if err != nil || len(challenge.Moves) != len(modes.RPSLSGame.PreferredOrder) {
+		t.Fatalf("Expected the challenge to use the rpsls moves, got %+v: %q", challenge, err)
+	}
+
+	if _, err := service.SubmitMove(challenge.SessionID, "U1", "spock"); err != nil {
+		t.Fatalf("Expected spock to be a valid move: %q", err)
+	}
+
+	v, _ := service.GameSessionsManager.Session(challenge.SessionID)
+	if command, _ := SessionCommand("mock", v); command.Mode != "rpsls" {
+		t.Fatalf("Expected the session's command to use its mode, got %+v", command)
+	}
+}
+
+func TestSessionCommandIgnoresOtherPlatforms(t *testing.T) {
+	session := server.GameSession{Challenger: "U1", Target: "U2", Data: CreateSessionData("other", Command{Channel: "general"})}
+
+	if _, ok := SessionCommand("mock", session); ok {
+		t.Fatal("Session from another platform should not be returned")
+	}
+	if command, ok := SessionCommand("other", session); !ok || command.Channel != "general" || command.Target.ID != "U2" {
+		t.Fatalf("Unexpected command: %+v", command)
+	}
+}
+
+func mention(player Player) string {
+	return "@" + player.Name
+}
+
+func TestRejectsMatchesWithoutPrivatePrompts(t *testing.T) {
+	command := mockCommand
+	command.BestOf = 3
+	adapter := newMockAdapter(command)
+	adapter.promptErr = ErrPromptUnsupported
+	service := NewService(server.NewGameServer(modes.StandardGame), adapter)
+
+	if _, err := service.StartChallenge(command); err != ErrMatchesUnsupported {
+		t.Fatalf("Expected the match to be rejected, got %v", err)
+	}
+
+	if len(service.GameSessionsManager.Sessions()) != 0 {
+		t.Fatal("Expected the match's session to be removed")
+	}
+}
+
+func TestCancelsSessionsWhosePromptsFail(t *testing.T) {
+	adapter := newMockAdapter(mockCommand)
+	adapter.promptErr = errors.New("Unknown tenant")
+	service := NewService(server.NewGameServer(modes.StandardGame), adapter)
+
+	if _, err := service.StartChallenge(mockCommand); err != adapter.promptErr {
+		t.Fatalf("Expected the prompt's error, got %v", err)
+	}
+
+	if len(service.GameSessionsManager.Sessions()) != 0 {
+		t.Fatal("Expected the unplayable session to be removed")
+	}
+}
+
+func receivePrompt(t *testing.T, adapter *mockAdapter) prompt {
+	select {
+	case prompt := <-adapter.prompts:
+		return prompt
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a prompt")
+	}
+
+	return prompt{}
+}
+
+func receiveResult(t *testing.T, adapter *mockAdapter) server.GameSession {
+	select {
+	case session := <-adapter.results:
+		return session
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the result to be posted")
+	}
+
+	return server.GameSession{}
+}
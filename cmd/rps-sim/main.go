@@ -0,0 +1,78 @@
+// Command rps-sim runs a headless tournament between bot strategies.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/bot"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/game/tournament"
+)
+
+func main() {
+	var strategies []string
+
+	mode := flag.String("mode", "standard", "game mode to simulate")
+	rounds := flag.Int("rounds", 1000, "rounds played by each pairing of strategies")
+	names := flag.String("strategies", "", "comma separated strategies to enter (default all)")
+	format := flag.String("format", "csv", "output format: csv or json")
+	seed := flag.Int64("seed", 0, "seed used for reproducible tournaments (default random)")
+	flag.Parse()
+
+	// A zero seed is a valid seed, so only an unset -seed picks a random one.
+	seedSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seedSet = true
+		}
+	})
+
+	registeredGame, ok := modes.RegisteredGames[*mode]
+	if !ok {
+		log.Fatalf("Unknown game mode: %v", *mode)
+	}
+
+	if *names == "" {
+		for name := range bot.RegisteredStrategies {
+			strategies = append(strategies, name)
+		}
+		sort.Strings(strategies)
+	} else {
+		strategies = strings.Split(*names, ",")
+	}
+
+	if !seedSet {
+		*seed = int64(game.DefaultRandomSource.Intn(math.MaxInt))
+		fmt.Fprintf(os.Stderr, "Using seed %v\n", *seed)
+	}
+
+	result, err := tournament.Run(tournament.Config{
+		Game:       registeredGame,
+		Rounds:     *rounds,
+		Strategies: strategies,
+		Seed:       *seed,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch *format {
+	case "csv":
+		err = result.WriteCSV(os.Stdout)
+	case "json":
+		err = result.WriteJSON(os.Stdout)
+	default:
+		err = fmt.Errorf("Unknown output format: %v", *format)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
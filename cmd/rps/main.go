@@ -0,0 +1,68 @@
+// Command rps plays games of RPS in the terminal, either hot-seat between two people or against a bot.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/bot"
+	"github.com/hamologist/rps/game/modes"
+)
+
+func main() {
+	mode := flag.String("mode", "standard", "game mode to play ("+strings.Join(registeredModes(), ", ")+")")
+	strategy := flag.String("bot", "", "play against a bot using the provided strategy instead of hot-seat")
+	bestOf := flag.Int("best-of", 1, "number of rounds in the match, the first to win a majority takes it")
+	rules := flag.Bool("rules", false, "print the rules table for the game mode and exit")
+	flag.Parse()
+
+	registeredGame, ok := modes.RegisteredGames[*mode]
+	if !ok {
+		log.Fatalf("Unknown game mode: %v", *mode)
+	}
+
+	if *rules {
+		printRules(os.Stdout, registeredGame)
+		return
+	}
+
+	if *bestOf < 1 || *bestOf%2 == 0 {
+		log.Fatalf("best-of must be a positive odd number: %v", *bestOf)
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	players := [2]player{
+		&humanPlayer{name: "Player One", in: in, hidden: *strategy == ""},
+	}
+
+	if *strategy == "" {
+		players[1] = &humanPlayer{name: "Player Two", in: in, hidden: true}
+	} else {
+		factory, ok := bot.RegisteredStrategies[*strategy]
+		if !ok {
+			log.Fatalf("Unknown bot strategy: %v", *strategy)
+		}
+		players[1] = &botPlayer{name: "Bot (" + *strategy + ")", strategy: factory(registeredGame, game.DefaultRandomSource)}
+	}
+
+	m := match{game: registeredGame, players: players, bestOf: *bestOf, out: os.Stdout}
+	if err := m.play(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func registeredModes() []string {
+	var names []string
+
+	for name := range modes.RegisteredGames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
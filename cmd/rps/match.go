@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/bot"
+)
+
+// match plays rounds between two players until one of them has won a majority of bestOf.
+// Drawn rounds are replayed and do not count towards bestOf.
+type match struct {
+	game    game.Game
+	players [2]player
+	bestOf  int
+	out     io.Writer
+}
+
+func (m *match) play() error {
+	var (
+		wins    [2]int
+		history [2][]bot.Round
+	)
+	needed := m.bestOf/2 + 1
+
+	for round := 1; wins[0] < needed && wins[1] < needed; round++ {
+		var moves [2]string
+
+		fmt.Fprintf(m.out, "Round %v (%v %v - %v %v)\n", round, m.players[0].Name(), wins[0], wins[1], m.players[1].Name())
+
+		for i, p := range m.players {
+			move, err := p.Move(m.game, m.out, history[i])
+			if err != nil {
+				return err
+			}
+			moves[i] = move
+		}
+
+		result, err := m.game.Play(moves[0], moves[1])
+		if err != nil {
+			return err
+		}
+
+		switch result {
+		case game.GameStatePlayerOneWins:
+			wins[0]++
+			fmt.Fprintf(m.out, "%v defeated %v, %v beats %v\n", m.players[0].Name(), m.players[1].Name(), moves[0], moves[1])
+		case game.GameStatePlayerTwoWins:
+			wins[1]++
+			fmt.Fprintf(m.out, "%v defeated %v, %v beats %v\n", m.players[1].Name(), m.players[0].Name(), moves[1], moves[0])
+		default:
+			fmt.Fprintf(m.out, "%v and %v had a draw. Both played %v\n", m.players[0].Name(), m.players[1].Name(), moves[0])
+		}
+
+		history[0] = append(history[0], bot.Round{Move: moves[0], OpponentMove: moves[1]})
+		history[1] = append(history[1], bot.Round{Move: moves[1], OpponentMove: moves[0]})
+	}
+
+	winner := m.players[0]
+	if wins[1] > wins[0] {
+		winner = m.players[1]
+	}
+
+	if m.bestOf > 1 {
+		fmt.Fprintf(m.out, "%v wins the match %v - %v\n", winner.Name(), max(wins[0], wins[1]), min(wins[0], wins[1]))
+	}
+
+	return nil
+}
+
+// printRules writes a table showing the outcome of every move (rows) against every other move (columns).
+func printRules(out io.Writer, g game.Game) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprint(writer, "\t")
+	for _, move := range g.PreferredOrder {
+		fmt.Fprintf(writer, "%v\t", move)
+	}
+	fmt.Fprintln(writer)
+
+	for _, move := range g.PreferredOrder {
+		fmt.Fprintf(writer, "%v\t", move)
+		for _, opponent := range g.PreferredOrder {
+			outcome := "lose"
+			result, _ := g.Play(move, opponent)
+
+			if result == game.GameStatePlayerOneWins {
+				outcome = "win"
+			} else if result == game.GameStateDraw {
+				outcome = "draw"
+			}
+			fmt.Fprintf(writer, "%v\t", outcome)
+		}
+		fmt.Fprintln(writer)
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/bot"
+	"github.com/hamologist/rps/game/modes"
+)
+
+func TestParseMoveAcceptsNamesAndPositions(t *testing.T) {
+	for input, expected := range map[string]string{"Rock": "rock", "2": "paper", " scissors ": "scissors"} {
+		if move, ok := parseMove(modes.StandardGame, input); !ok || move != expected {
+			t.Fatalf("Expected %q to parse as %q, got %q", input, expected, move)
+		}
+	}
+
+	for _, input := range []string{"0", "4", "lizard", ""} {
+		if _, ok := parseMove(modes.StandardGame, input); ok {
+			t.Fatalf("Expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestMatchPlaysUntilMajority(t *testing.T) {
+	var out bytes.Buffer
+	in := bufio.NewReader(strings.NewReader("paper\nrock\nlizard\n2\n"))
+	m := match{
+		game: modes.StandardGame,
+		players: [2]player{
+			&humanPlayer{name: "Player One", in: in},
+			&botPlayer{name: "Bot", strategy: bot.NewConstant(modes.StandardGame, game.NewSeededSource(1))},
+		},
+		bestOf: 3,
+		out:    &out,
+	}
+
+	if err := m.play(); err != nil {
+		t.Fatalf("Match should not have caused an error: %q", err)
+	}
+
+	if !strings.Contains(out.String(), "had a draw") || !strings.Contains(out.String(), "Player One wins the match 2 - 0") {
+		t.Fatalf("Unexpected match output:\n%v", out.String())
+	}
+}
+
+func TestPrintRules(t *testing.T) {
+	var out bytes.Buffer
+	printRules(&out, modes.StandardGame)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+	if len(lines) != 4 || strings.Fields(lines[1])[0] != "rock" || strings.Fields(lines[1])[3] != "win" {
+		t.Fatalf("Unexpected rules table:\n%v", out.String())
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/bot"
+)
+
+// player chooses moves for one side of a match.
+type player interface {
+	Name() string
+	Move(g game.Game, out io.Writer, history []bot.Round) (string, error)
+}
+
+// humanPlayer reads moves from the terminal.
+// Hidden players type their move without it being echoed, so hot-seat opponents can't peek.
+type humanPlayer struct {
+	name   string
+	in     *bufio.Reader
+	hidden bool
+}
+
+func (human *humanPlayer) Name() string {
+	return human.name
+}
+
+func (human *humanPlayer) Move(g game.Game, out io.Writer, history []bot.Round) (string, error) {
+	var choices []string
+
+	for i, move := range g.PreferredOrder {
+		choices = append(choices, fmt.Sprintf("%v) %v", i+1, move))
+	}
+
+	for {
+		fmt.Fprintf(out, "%v, choose your move [%v]: ", human.name, strings.Join(choices, ", "))
+
+		line, err := human.readLine(out)
+		if err != nil {
+			return "", err
+		}
+
+		if move, ok := parseMove(g, line); ok {
+			return move, nil
+		}
+		fmt.Fprintf(out, "%q is not a valid move.\n", line)
+	}
+}
+
+func (human *humanPlayer) readLine(out io.Writer) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	if human.hidden && term.IsTerminal(fd) {
+		line, err := term.ReadPassword(fd)
+		fmt.Fprintln(out)
+		return strings.TrimSpace(string(line)), err
+	}
+
+	line, err := human.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// botPlayer picks moves using a bot.Strategy.
+type botPlayer struct {
+	name     string
+	strategy bot.Strategy
+}
+
+func (computer *botPlayer) Name() string {
+	return computer.name
+}
+
+func (computer *botPlayer) Move(g game.Game, out io.Writer, history []bot.Round) (string, error) {
+	return computer.strategy.Move(history), nil
+}
+
+// parseMove accepts either a move's name or its 1-based position in the game's PreferredOrder.
+func parseMove(g game.Game, input string) (string, bool) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	if i, err := strconv.Atoi(input); err == nil {
+		if i < 1 || i > len(g.PreferredOrder) {
+			return "", false
+		}
+		return g.PreferredOrder[i-1], true
+	}
+
+	if _, ok := g.Moves[input]; ok {
+		return input, true
+	}
+
+	return "", false
+}
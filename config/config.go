@@ -0,0 +1,264 @@
+// Package config loads the configuration of the RPS server.
+// Values are read from an optional JSON config file, then from RPS_* env variables, then from flags,
+// each source overriding the previous one.
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/hamologist/rps/game/modes"
+)
+
+const (
+	// DefaultPort is the port the server listens on when none is configured.
+	DefaultPort = "8081"
+	// DefaultGame is the game mode used when none is configured.
+	DefaultGame = "standard"
+	// DefaultSlackCommandName is the slash command mentioned to users once the app is installed.
+	DefaultSlackCommandName = "rps-accept"
+)
+
+// Config defines the configuration of the RPS server.
+type Config struct {
+	Port        string   `json:"port"`
+	Game        string   `json:"game"`  // The name of one of the modes.RegisteredGames.
+	Debug       bool     `json:"debug"` // Logs debug records, which include the requests received from chat platforms.
+	APITokens   []string `json:"api_tokens"`
+	AdminTokens []string `json:"admin_tokens"` // The admin API for managing sessions is only served when set.
+	GRPCPort    string   `json:"grpc_port"`    // The gRPC API is only served when set, and requires APITokens.
+	PublicURL   string   `json:"public_url"`
+	AuditFile   string   `json:"audit_file"` // The JSON-lines file game events are recorded to, auditing is disabled when empty.
+	Slack       Slack    `json:"slack"`
+	Discord     Discord  `json:"discord"`
+	Teams       Teams    `json:"teams"`
+}
+
+// Slack defines the configuration of the Slack integration.
+type Slack struct {
+	OAuthToken        string   `json:"oauth_token"` // Used for any workspace the app wasn't installed in through the OAuth flow.
+	ClientID          string   `json:"client_id"`
+	ClientSecret      string   `json:"client_secret"`
+	RedirectURL       string   `json:"redirect_url"`
+	SigningSecret     string   `json:"signing_secret"` // Verifies every request from Slack, the Events API and admin commands are only enabled when set.
+	BaseURL           string   `json:"base_url"`       // The base URL of the Slack Web API, slack.DefaultBaseURL when empty.
+	TokenFile         string   `json:"token_file"`     // Persists the workspaces' bot tokens, which are kept in memory when empty.
+	CommandName       string   `json:"command_name"`
+	LegacyAttachments bool     `json:"legacy_attachments"`
+	Admins            []string `json:"admins"` // The IDs of the users allowed to look up the history of sessions, requires SigningSecret.
+}
+
+// Discord defines the configuration of the Discord integration, which is enabled when PublicKey is set.
+type Discord struct {
+	PublicKey string `json:"public_key"` // The hex encoded Ed25519 public key of the Discord application.
+}
+
+// Teams defines the configuration of the Microsoft Teams integration, which is enabled when Secret is set.
+// Players are sent their links privately by the Teams bot identified by AppID and AppPassword.
+type Teams struct {
+	Secret          string `json:"secret"` // The base64 security token of the outgoing webhook.
+	IncomingWebhook string `json:"incoming_webhook"`
+	AppID           string `json:"app_id"`
+	AppPassword     string `json:"app_password"`
+}
+
+// Default returns the configuration used for anything that isn't configured.
+func Default() Config {
+	return Config{
+		Port: DefaultPort,
+		Game: DefaultGame,
+		Slack: Slack{
+			CommandName: DefaultSlackCommandName,
+		},
+	}
+}
+
+// Load loads and validates the configuration from the config file, env variables (looked up using getenv) and the flags in args.
+// The config file is set using the -config flag or the RPS_CONFIG env variable.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	var configFile string
+
+	// Flags are parsed twice, first for finding the config file, then for overriding the other sources.
+	scratch := Default()
+	if err := newFlagSet(&scratch, &configFile).Parse(args); err != nil {
+		return Config{}, err
+	}
+	if configFile == "" {
+		configFile = getenv("RPS_CONFIG")
+	}
+
+	config := Default()
+	if configFile != "" {
+		if err := config.loadFile(configFile); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := config.loadEnv(getenv); err != nil {
+		return Config{}, err
+	}
+
+	if err := newFlagSet(&config, &configFile).Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	return config, config.Validate()
+}
+
+// Validate returns an error describing the first invalid value of the configuration.
+func (config Config) Validate() error {
+	if port, err := strconv.Atoi(config.Port); err != nil || port < 0 || port > 65535 {
+		return fmt.Errorf("The port must be a number between 0 and 65535, got %q", config.Port)
+	}
+
+	if _, ok := modes.RegisteredGames[config.Game]; !ok {
+		return fmt.Errorf("Unknown game mode %q", config.Game)
+	}
+
+	if config.GRPCPort != "" && len(config.APITokens) == 0 {
+		return errors.New("The gRPC API requires API tokens")
+	}
+
+	if (config.Slack.ClientID == "") != (config.Slack.ClientSecret == "") {
+		return errors.New("The Slack client ID and client secret must be set together")
+	}
+
+	if config.Discord.PublicKey != "" {
+		if _, err := config.Discord.Key(); err != nil {
+			return err
+		}
+	}
+
+	if config.Teams.Secret != "" {
+		if _, err := config.Teams.SecretBytes(); err != nil {
+			return err
+		}
+		if config.PublicURL == "" {
+			return errors.New("The public URL must be set for building the player links sent to Teams")
+		}
+		if config.Teams.AppID == "" || config.Teams.AppPassword == "" {
+			return errors.New("The Teams bot's app ID and password must be set for sending players their links privately")
+		}
+	}
+
+	return nil
+}
+
+// Addr returns the address the server listens on.
+func (config Config) Addr() string {
+	return ":" + config.Port
+}
+
+// Key decodes the Discord application's public key.
+func (discord Discord) Key() (ed25519.PublicKey, error) {
+	publicKey, err := hex.DecodeString(discord.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, errors.New("The Discord public key must be a hex encoded Ed25519 public key")
+	}
+
+	return ed25519.PublicKey(publicKey), nil
+}
+
+// SecretBytes decodes the outgoing webhook's security token.
+func (teams Teams) SecretBytes() ([]byte, error) {
+	secret, err := base64.StdEncoding.DecodeString(teams.Secret)
+	if err != nil {
+		return nil, errors.New("The Teams secret must be the base64 security token of the outgoing webhook")
+	}
+
+	return secret, nil
+}
+
+func (config *Config) loadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("The config file %v is invalid: %v", path, err)
+	}
+
+	return nil
+}
+
+func (config *Config) loadEnv(getenv func(string) string) error {
+	for name, value := range map[string]*string{
+		"RPS_PORT":                   &config.Port,
+		"RPS_GAME":                   &config.Game,
+		"RPS_GRPC_PORT":              &config.GRPCPort,
+		"RPS_PUBLIC_URL":             &config.PublicURL,
+		"RPS_AUDIT_FILE":             &config.AuditFile,
+		"RPS_SLACK_OAUTH":            &config.Slack.OAuthToken,
+		"RPS_SLACK_CLIENT_ID":        &config.Slack.ClientID,
+		"RPS_SLACK_CLIENT_SECRET":    &config.Slack.ClientSecret,
+		"RPS_SLACK_REDIRECT_URL":     &config.Slack.RedirectURL,
+		"RPS_SLACK_SIGNING_SECRET":   &config.Slack.SigningSecret,
+		"RPS_SLACK_API_URL":          &config.Slack.BaseURL,
+		"RPS_SLACK_TOKEN_FILE":       &config.Slack.TokenFile,
+		"RPS_COMMAND_NAME":           &config.Slack.CommandName,
+		"RPS_DISCORD_PUBLIC_KEY":     &config.Discord.PublicKey,
+		"RPS_TEAMS_SECRET":           &config.Teams.Secret,
+		"RPS_TEAMS_INCOMING_WEBHOOK": &config.Teams.IncomingWebhook,
+		"RPS_TEAMS_APP_ID":           &config.Teams.AppID,
+		"RPS_TEAMS_APP_PASSWORD":     &config.Teams.AppPassword,
+	} {
+		if env := getenv(name); env != "" {
+			*value = env
+		}
+	}
+
+	for name, value := range map[string]*bool{
+		"RPS_DEBUG":                    &config.Debug,
+		"RPS_SLACK_LEGACY_ATTACHMENTS": &config.Slack.LegacyAttachments,
+	} {
+		if env := getenv(name); env != "" {
+			enabled, err := strconv.ParseBool(env)
+			if err != nil {
+				return fmt.Errorf("The \"%v\" env variable must be a boolean, got %q", name, env)
+			}
+			*value = enabled
+		}
+	}
+
+	if env := getenv("RPS_API_TOKENS"); env != "" {
+		config.APITokens = strings.Split(env, ",")
+	}
+
+	if env := getenv("RPS_ADMIN_TOKENS"); env != "" {
+		config.AdminTokens = strings.Split(env, ",")
+	}
+
+	if env := getenv("RPS_SLACK_ADMINS"); env != "" {
+		config.Slack.Admins = strings.Split(env, ",")
+	}
+
+	return nil
+}
+
+// newFlagSet creates the flags for the configuration's values that aren't secrets, defaulting to their current values.
+func newFlagSet(config *Config, configFile *string) *flag.FlagSet {
+	flags := flag.NewFlagSet("rps", flag.ContinueOnError)
+
+	flags.StringVar(configFile, "config", *configFile, "path of a JSON config file")
+	flags.StringVar(&config.Port, "port", config.Port, "port the server listens on")
+	flags.StringVar(&config.Game, "game", config.Game, "game mode used by sessions without a mode")
+	flags.BoolVar(&config.Debug, "debug", config.Debug, "log debug records, including the requests received from chat platforms")
+	flags.StringVar(&config.GRPCPort, "grpc-port", config.GRPCPort, "port the gRPC API listens on, disabled when empty")
+	flags.StringVar(&config.PublicURL, "public-url", config.PublicURL, "URL the server is reachable at, used for player links")
+	flags.StringVar(&config.AuditFile, "audit-file", config.AuditFile, "JSON-lines file game events are recorded to, disabled when empty")
+	flags.StringVar(&config.Slack.BaseURL, "slack-api-url", config.Slack.BaseURL, "base URL of the Slack Web API (default https://slack.com/api)")
+	flags.StringVar(&config.Slack.TokenFile, "slack-token-file", config.Slack.TokenFile, "file persisting the bot tokens of installed workspaces")
+	flags.StringVar(&config.Slack.CommandName, "slack-command-name", config.Slack.CommandName, "slash command mentioned once the app is installed")
+	flags.BoolVar(&config.Slack.LegacyAttachments, "slack-legacy-attachments", config.Slack.LegacyAttachments, "use interactive message attachments instead of Block Kit")
+
+	return flags
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func env(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	config, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Addr() != ":"+DefaultPort || config.Game != DefaultGame || config.Slack.CommandName != DefaultSlackCommandName {
+		t.Fatalf("Unexpected defaults: %+v", config)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rps-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(configFile, []byte(`{"port": "9000", "game": "rpsls", "debug": true, "slack": {"oauth_token": "xoxb-file", "command_name": "rps"}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(
+		[]string{"-port", "9002"},
+		env(map[string]string{"RPS_CONFIG": configFile, "RPS_PORT": "9001", "RPS_SLACK_OAUTH": "xoxb-env", "RPS_API_TOKENS": "a,b"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Port != "9002" {
+		t.Fatalf("Expected flags to override env variables, got port %v", config.Port)
+	}
+	if config.Slack.OAuthToken != "xoxb-env" || len(config.APITokens) != 2 {
+		t.Fatalf("Expected env variables to override the config file, got %+v", config)
+	}
+	if config.Game != "rpsls" || !config.Debug || config.Slack.CommandName != "rps" {
+		t.Fatalf("Expected the config file to override the defaults, got %+v", config)
+	}
+}
+
+func TestLoadValidates(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		env      map[string]string
+		expected string
+	}{
+		{[]string{"-port", "http"}, nil, "The port must be a number"},
+		{[]string{"-game", "chess"}, nil, "Unknown game mode"},
+		{[]string{"-grpc-port", "9090"}, nil, "The gRPC API requires API tokens"},
+		{nil, map[string]string{"RPS_SLACK_CLIENT_ID": "client"}, "client secret must be set together"},
+		{nil, map[string]string{"RPS_DISCORD_PUBLIC_KEY": "abc"}, "The Discord public key"},
+		{nil, map[string]string{"RPS_TEAMS_SECRET": "c2VjcmV0"}, "The public URL must be set"},
+		{nil, map[string]string{"RPS_DEBUG": "sometimes"}, "must be a boolean"},
+	} {
+		_, err := Load(test.args, env(test.env))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Fatalf("Expected an error containing %q for %v %v, got %v", test.expected, test.args, test.env, err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package discord
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/server"
+)
+
+// Platform is the chat platform name stored with sessions created through Discord.
+const Platform = "discord"
+
+// adapter implements chat.Adapter for Discord interactions.
+// Discord can only message a single player in response to their own interaction, so the move buttons are part of the challenge.
+type adapter struct {
+	publicKey ed25519.PublicKey
+}
+
+func (adapter *adapter) Platform() string {
+	return Platform
+}
+
+func (adapter *adapter) ParseCommand(r *http.Request) (chat.Command, error) {
+	interaction, err := adapter.readInteraction(r)
+	if err != nil {
+		return chat.Command{}, err
+	}
+
+	return commandFromInteraction(interaction)
+}
+
+// readInteraction verifies the request was sent by Discord and decodes the interaction it carries.
+func (adapter *adapter) readInteraction(r *http.Request) (Interaction, error) {
+	var interaction Interaction
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Failed to read the interaction", "error", err)
+		return interaction, errors.New("An error occurred while proccessing your request")
+	}
+
+	if !adapter.verify(r.Header, body) {
+		return interaction, chat.ErrUnauthorized
+	}
+
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		slog.WarnContext(r.Context(), "Failed to decode the interaction", "error", err)
+		return interaction, errors.New("An error occurred while proccessing your request")
+	}
+
+	return interaction, nil
+}
+
+// verify checks the Ed25519 signature Discord attaches to every interaction request.
+func (adapter *adapter) verify(header http.Header, body []byte) bool {
+	signature, err := hex.DecodeString(header.Get(signatureHeader))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+
+	message := bytes.NewBufferString(header.Get(timestampHeader))
+	message.Write(body)
+
+	return ed25519.Verify(adapter.publicKey, message.Bytes(), signature)
+}
+
+// commandFromInteraction parses the challenge issued by the application command.
+func commandFromInteraction(interaction Interaction) (chat.Command, error) {
+	var target string
+
+	if interaction.Data.Name != CommandName {
+		return chat.Command{}, errors.New("Unknown command.")
+	}
+
+	// Commands registered before the subcommands were added pass the opponent directly.
+	options := interaction.Data.Options
+	if len(options) == 1 && options[0].Type == CommandOptionTypeSubCommand {
+		options = options[0].Options
+	}
+
+	for _, option := range options {
+		if option.Name == OpponentOption {
+			target, _ = option.Value.(string)
+		}
+	}
+
+	challenger := interaction.Invoker()
+	targetInfo, ok := interaction.Data.Resolved.Users[target]
+
+	if !ok {
+		return chat.Command{}, errors.New("The user you are attempting to challenge isn't a valid Discord user.")
+	}
+
+	if targetInfo.Bot || targetInfo.ID == challenger.ID {
+		return chat.Command{}, errors.New("You need to challenge another person.")
+	}
+
+	return chat.Command{
+		Challenger: chat.Player{ID: challenger.ID, Name: challenger.Username},
+		Target:     chat.Player{ID: targetInfo.ID, Name: targetInfo.Username},
+		Channel:    interaction.ChannelID,
+		Workspace:  interaction.GuildID,
+	}, nil
+}
+
+// RenderChallenge posts the challenge to the channel, with a button for each move and one for withdrawing the challenge.
+func (adapter *adapter) RenderChallenge(w http.ResponseWriter, challenge chat.Challenge) error {
+	var (
+		rows    []Component
+		buttons []Component
+	)
+
+	for _, move := range challenge.Moves {
+		customID, err := json.Marshal(componentValue{SessionID: challenge.SessionID, Move: move})
+		if err != nil {
+			return err
+		}
+
+		buttons = append(buttons, Component{
+			Type:     ComponentTypeButton,
+			Style:    ButtonStylePrimary,
+			Label:    game.DisplayName(move),
+			CustomID: string(customID),
+		})
+	}
+
+	for len(buttons) > 0 {
+		n := len(buttons)
+		if n > maxButtonsPerRow {
+			n = maxButtonsPerRow
+		}
+		rows = append(rows, Component{Type: ComponentTypeActionRow, Components: buttons[:n]})
+		buttons = buttons[n:]
+	}
+
+	cancelID, err := json.Marshal(componentValue{SessionID: challenge.SessionID, Action: cancelAction})
+	if err != nil {
+		return err
+	}
+	rows = append(rows, Component{Type: ComponentTypeActionRow, Components: []Component{{
+		Type:     ComponentTypeButton,
+		Style:    ButtonStyleDanger,
+		Label:    "Cancel",
+		CustomID: string(cancelID),
+	}}})
+
+	writeResponse(w, InteractionResponse{
+		Type: ResponseTypeChannelMessage,
+		Data: &ResponseData{
+			Content: fmt.Sprintf(
+				"<@%v> has challenged <@%v> to a game of RPS. Please select your move.",
+				challenge.Challenger.ID,
+				challenge.Target.ID,
+			),
+			Components:      rows,
+			AllowedMentions: &AllowedMentions{Users: []string{challenge.Target.ID}},
+		},
+	})
+
+	return nil
+}
+
+// SendPrivatePrompt is unsupported, both players pick their move through the buttons of the rendered challenge.
+func (adapter *adapter) SendPrivatePrompt(challenge chat.Challenge, player chat.Player) error {
+	return chat.ErrPromptUnsupported
+}
+
+// PostResult leaves the channel untouched, the result replaces the challenge in response to the final move.
+func (adapter *adapter) PostResult(session server.GameSession) error {
+	return nil
+}
+
+func (adapter *adapter) RenderError(w http.ResponseWriter, message string) {
+	writeResponse(w, ephemeralResponse(message))
+}
+
+// formatResult describes a completed session, mentioning both players.
+func formatResult(session server.GameSession) string {
+	return chat.FormatResult(session, func(player chat.Player) string {
+		return "<@" + player.ID + ">"
+	})
+}
@@ -0,0 +1,146 @@
+// Package discord provides a Discord interactions integration backed by a server.GameServer.
+package discord
+
+const (
+	// InteractionTypePing is sent by Discord to verify the interactions endpoint.
+	InteractionTypePing = 1
+	// InteractionTypeApplicationCommand is sent when a user invokes a slash command.
+	InteractionTypeApplicationCommand = 2
+	// InteractionTypeMessageComponent is sent when a user clicks a message component (such as a button).
+	InteractionTypeMessageComponent = 3
+
+	// ResponseTypePong acknowledges a Ping.
+	ResponseTypePong = 1
+	// ResponseTypeChannelMessage responds to an interaction with a new message.
+	ResponseTypeChannelMessage = 4
+	// ResponseTypeUpdateMessage responds to a component interaction by editing the message the component was on.
+	ResponseTypeUpdateMessage = 7
+
+	// ComponentTypeActionRow is a container for up to five buttons.
+	ComponentTypeActionRow = 1
+	// ComponentTypeButton is a clickable button.
+	ComponentTypeButton = 2
+
+	// ButtonStylePrimary is the blurple button style.
+	ButtonStylePrimary = 1
+	// ButtonStyleDanger is the red button style.
+	ButtonStyleDanger = 4
+
+	// CommandOptionTypeSubCommand is a slash command option selecting a subcommand, holding the subcommand's options.
+	CommandOptionTypeSubCommand = 1
+	// CommandOptionTypeUser is a slash command option selecting a user.
+	CommandOptionTypeUser = 6
+
+	// MessageFlagEphemeral makes a message only visible to the user that invoked the interaction.
+	MessageFlagEphemeral = 1 << 6
+
+	// maxButtonsPerRow is the maximum number of buttons Discord allows in a single action row.
+	maxButtonsPerRow = 5
+)
+
+// Interaction provides a way to structure and work with the interactions Discord sends to the application.
+type Interaction struct {
+	ID            string          `json:"id"`
+	ApplicationID string          `json:"application_id"`
+	Type          int             `json:"type"`
+	Data          InteractionData `json:"data"`
+	GuildID       string          `json:"guild_id"`
+	ChannelID     string          `json:"channel_id"`
+	Member        *Member         `json:"member,omitempty"`
+	User          *User           `json:"user,omitempty"`
+	Token         string          `json:"token"` // Allows editing the interaction's response for 15 minutes.
+}
+
+// Invoker returns the user that triggered the interaction.
+// Discord provides the user through Member in guilds, and through User in DMs.
+func (interaction *Interaction) Invoker() User {
+	if interaction.Member != nil && interaction.Member.User != nil {
+		return *interaction.Member.User
+	}
+
+	if interaction.User != nil {
+		return *interaction.User
+	}
+
+	return User{}
+}
+
+// InteractionData defines the command or component data associated to an Interaction.
+type InteractionData struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Options  []CommandOption `json:"options"`
+	Resolved Resolved        `json:"resolved"`
+	CustomID string          `json:"custom_id"`
+}
+
+// CommandOption defines an option passed to a slash command.
+// Subcommands are passed as an option holding the subcommand's own options.
+type CommandOption struct {
+	Name    string          `json:"name"`
+	Type    int             `json:"type"`
+	Value   interface{}     `json:"value"`
+	Options []CommandOption `json:"options"`
+}
+
+// Resolved contains the full objects for any users referenced by a command's options.
+type Resolved struct {
+	Users map[string]User `json:"users"`
+}
+
+// Member defines data about the guild member that executed the interaction.
+type Member struct {
+	User *User  `json:"user"`
+	Nick string `json:"nick"`
+}
+
+// User defines data about a Discord user.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Bot      bool   `json:"bot"`
+}
+
+// InteractionResponse is used to respond to an Interaction.
+type InteractionResponse struct {
+	Type int           `json:"type"`
+	Data *ResponseData `json:"data,omitempty"`
+}
+
+// ResponseData defines the message sent with an InteractionResponse.
+type ResponseData struct {
+	Content         string           `json:"content"`
+	Flags           int              `json:"flags,omitempty"`
+	Components      []Component      `json:"components"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+}
+
+// AllowedMentions controls which mentions in a message notify users.
+type AllowedMentions struct {
+	Users []string `json:"users"`
+}
+
+// Component defines an interactive message element.
+type Component struct {
+	Type       int         `json:"type"`
+	Style      int         `json:"style,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	CustomID   string      `json:"custom_id,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+// ApplicationCommand defines a slash command, used for registering commands with Discord.
+type ApplicationCommand struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Options     []ApplicationCommandOption `json:"options"`
+}
+
+// ApplicationCommandOption defines an option accepted by an ApplicationCommand.
+type ApplicationCommandOption struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Type        int                        `json:"type"`
+	Required    bool                       `json:"required"`
+	Options     []ApplicationCommandOption `json:"options,omitempty"`
+}
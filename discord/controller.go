@@ -0,0 +1,334 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/logging"
+	"github.com/hamologist/rps/server"
+)
+
+const (
+	// CommandName is the name of the slash command used to issue and withdraw challenges.
+	CommandName = "rps"
+	// ChallengeSubcommand is the CommandName subcommand used to issue a challenge.
+	ChallengeSubcommand = "challenge"
+	// CancelSubcommand is the CommandName subcommand used to withdraw the invoker's pending challenge.
+	CancelSubcommand = "cancel"
+	// OpponentOption is the name of the ChallengeSubcommand option used to select the user being challenged.
+	OpponentOption = "opponent"
+
+	// cancelAction is the componentValue action of the button withdrawing a challenge.
+	cancelAction = "cancel"
+
+	// apiTimeout bounds the requests made to Discord's API.
+	apiTimeout = 10 * time.Second
+
+	signatureHeader = "X-Signature-Ed25519"
+	timestampHeader = "X-Signature-Timestamp"
+)
+
+// Command is the slash command handled by the controller, for registering with Discord's API.
+var Command = ApplicationCommand{
+	Name:        CommandName,
+	Description: "Play a game of RPS",
+	Options: []ApplicationCommandOption{
+		{
+			Name:        ChallengeSubcommand,
+			Description: "Challenge someone to a game of RPS",
+			Type:        CommandOptionTypeSubCommand,
+			Options: []ApplicationCommandOption{
+				{
+					Name:        OpponentOption,
+					Description: "The user you want to challenge",
+					Type:        CommandOptionTypeUser,
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        CancelSubcommand,
+			Description: "Withdraw your pending challenge in this channel",
+			Type:        CommandOptionTypeSubCommand,
+		},
+	},
+}
+
+type componentValue struct {
+	Move      string `json:"move,omitempty"`
+	SessionID string `json:"session_id"`
+	Action    string `json:"action,omitempty"` // Set for buttons other than moves, such as cancelAction.
+}
+
+// originalMessage identifies the response of the interaction that issued a challenge, for editing it once withdrawn.
+type originalMessage struct {
+	applicationID string
+	token         string
+}
+
+type controller struct {
+	*chat.Service
+	adapter    *adapter
+	apiURL     string
+	httpClient *http.Client
+	// The messages of pending challenges, kept in memory rather than in the session's Data as the token grants access to the message.
+	mutex    sync.Mutex
+	messages map[string]originalMessage
+}
+
+func (controller *controller) HandleInteraction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	interaction, err := controller.adapter.readInteraction(r)
+	if err == chat.ErrUnauthorized {
+		http.Error(w, "Invalid request signature", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch interaction.Type {
+	case InteractionTypePing:
+		writeResponse(w, InteractionResponse{Type: ResponseTypePong})
+	case InteractionTypeApplicationCommand:
+		controller.processCommand(w, interaction)
+	case InteractionTypeMessageComponent:
+		writeResponse(w, controller.processComponent(interaction))
+	default:
+		http.Error(w, "Unsupported interaction type", http.StatusBadRequest)
+	}
+}
+
+// processCommand issues a challenge through the chat.Service, or withdraws one for the cancel subcommand.
+func (controller *controller) processCommand(w http.ResponseWriter, interaction Interaction) {
+	options := interaction.Data.Options
+	if interaction.Data.Name == CommandName && len(options) == 1 && options[0].Type == CommandOptionTypeSubCommand && options[0].Name == CancelSubcommand {
+		writeResponse(w, controller.processCancelCommand(interaction))
+		return
+	}
+
+	command, err := commandFromInteraction(interaction)
+	if err != nil {
+		controller.adapter.RenderError(w, err.Error())
+		return
+	}
+
+	challenge, err := controller.StartChallenge(command)
+	if err != nil {
+		slog.Error("Failed to issue the challenge", logging.SessionKey, challenge.SessionID, "error", err)
+		controller.adapter.RenderError(w, chat.ErrorMessage(err, "There was a problem issuing the challenge. Please try again."))
+		return
+	}
+
+	if interaction.ApplicationID != "" && interaction.Token != "" {
+		controller.mutex.Lock()
+		controller.messages[challenge.SessionID] = originalMessage{applicationID: interaction.ApplicationID, token: interaction.Token}
+		controller.mutex.Unlock()
+	}
+
+	if err := controller.adapter.RenderChallenge(w, challenge); err != nil {
+		slog.Error("Failed to render the challenge", logging.SessionKey, challenge.SessionID, "error", err)
+		controller.adapter.RenderError(w, "An error occurred while building the moves for the game")
+	}
+}
+
+func (controller *controller) processComponent(interaction Interaction) InteractionResponse {
+	var value componentValue
+
+	if err := json.Unmarshal([]byte(interaction.Data.CustomID), &value); err != nil {
+		return ephemeralResponse("There was a problem processing the game move payload.")
+	}
+
+	if value.Action == cancelAction {
+		return controller.processCancelButton(interaction, value)
+	}
+
+	v, err := controller.SubmitMove(value.SessionID, interaction.Invoker().ID, value.Move)
+	switch err {
+	case nil:
+	case server.ErrSessionNotFound:
+		return ephemeralResponse("An invalid session id was passed with your move. Maybe the game session has expired.")
+	case server.ErrNotSessionPlayer:
+		return ephemeralResponse("Only the players in this game can submit a move.")
+	case server.ErrMoveLocked:
+		return ephemeralResponse("You have already locked in your move.")
+	case server.ErrSessionComplete:
+		return ephemeralResponse("This game has already been played.")
+	default:
+		return ephemeralResponse(err.Error())
+	}
+
+	if v.Result == "" {
+		return ephemeralResponse("Your move has been locked in")
+	}
+
+	if _, ok := chat.SessionCommand(Platform, v); !ok {
+		return ephemeralResponse("Game session does not support Discord.")
+	}
+
+	return InteractionResponse{
+		Type: ResponseTypeUpdateMessage,
+		Data: &ResponseData{
+			Content:         formatResult(v),
+			Components:      []Component{},
+			AllowedMentions: &AllowedMentions{Users: []string{}},
+		},
+	}
+}
+
+// processCancelButton withdraws the challenge the button was on, replacing the challenge with a withdrawn message.
+// The message is replaced by the response, so it isn't edited through replaceChallenge.
+func (controller *controller) processCancelButton(interaction Interaction, value componentValue) InteractionResponse {
+	message, ok := controller.takeMessage(value.SessionID)
+	v, err := controller.GameSessionsManager.CancelSession(value.SessionID, interaction.Invoker().ID)
+	if err != nil {
+		// Only the challenger's failed attempt leaves the challenge pending.
+		if ok && err == server.ErrNotSessionChallenger {
+			controller.mutex.Lock()
+			controller.messages[value.SessionID] = message
+			controller.mutex.Unlock()
+		}
+		return cancelErrorResponse(err)
+	}
+
+	return InteractionResponse{
+		Type: ResponseTypeUpdateMessage,
+		Data: &ResponseData{
+			Content:         withdrawnContent(v),
+			Components:      []Component{},
+			AllowedMentions: &AllowedMentions{Users: []string{v.Target}},
+		},
+	}
+}
+
+// processCancelCommand withdraws the invoker's latest pending challenge in the channel, notifying the target.
+func (controller *controller) processCancelCommand(interaction Interaction) InteractionResponse {
+	var id string
+
+	challenger := interaction.Invoker()
+	for _, v := range controller.GameSessionsManager.Sessions() {
+		command, ok := chat.SessionCommand(Platform, v)
+		if ok && v.Challenger == challenger.ID && v.Result == "" && command.Channel == interaction.ChannelID {
+			id = v.ID
+		}
+	}
+
+	if id == "" {
+		return ephemeralResponse("You have no pending challenge to withdraw in this channel.")
+	}
+
+	v, err := controller.GameSessionsManager.CancelSession(id, challenger.ID)
+	if err != nil {
+		return cancelErrorResponse(err)
+	}
+
+	return InteractionResponse{
+		Type: ResponseTypeChannelMessage,
+		Data: &ResponseData{
+			Content:         withdrawnContent(v),
+			Components:      []Component{},
+			AllowedMentions: &AllowedMentions{Users: []string{v.Target}},
+		},
+	}
+}
+
+// handleEvent forgets the message of a challenge once it can no longer be withdrawn,
+// replacing the message when the challenge was withdrawn by any other means than its Cancel button.
+func (controller *controller) handleEvent(event server.Event) {
+	switch event.Type {
+	case server.EventResult, server.EventExpired, server.EventCancelled:
+	default:
+		return
+	}
+
+	message, ok := controller.takeMessage(event.Session.ID)
+	if ok && event.Type == server.EventCancelled {
+		go controller.replaceChallenge(event.Session, message)
+	}
+}
+
+func (controller *controller) takeMessage(id string) (originalMessage, bool) {
+	controller.mutex.Lock()
+	defer controller.mutex.Unlock()
+
+	message, ok := controller.messages[id]
+	delete(controller.messages, id)
+
+	return message, ok
+}
+
+// replaceChallenge replaces the buttons of a withdrawn challenge's message, by editing the response of the interaction that issued it.
+// Discord only allows editing the response for 15 minutes, older challenges keep their buttons, which then report the session as expired.
+func (controller *controller) replaceChallenge(v server.GameSession, message originalMessage) {
+	body, err := json.Marshal(ResponseData{
+		Content:         withdrawnContent(v),
+		Components:      []Component{},
+		AllowedMentions: &AllowedMentions{Users: []string{}},
+	})
+	if err != nil {
+		slog.Error("Failed to replace the withdrawn challenge", logging.SessionKey, v.ID, "error", err)
+		return
+	}
+
+	route := fmt.Sprintf("%v/webhooks/%v/%v/messages/@original", controller.apiURL, message.applicationID, message.token)
+	req, err := http.NewRequest("PATCH", route, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("Failed to replace the withdrawn challenge", logging.SessionKey, v.ID, "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := controller.httpClient.Do(req)
+	if err != nil {
+		slog.Error("Failed to replace the withdrawn challenge", logging.SessionKey, v.ID, "error", err)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		slog.Error("Failed to replace the withdrawn challenge", logging.SessionKey, v.ID, "status", resp.StatusCode)
+	}
+}
+
+func cancelErrorResponse(err error) InteractionResponse {
+	switch err {
+	case server.ErrSessionNotFound:
+		return ephemeralResponse("The challenge has already expired.")
+	case server.ErrSessionComplete:
+		return ephemeralResponse("This game has already been played.")
+	default:
+		return ephemeralResponse(err.Error())
+	}
+}
+
+func withdrawnContent(v server.GameSession) string {
+	return fmt.Sprintf("Challenge withdrawn: <@%v> withdrew their challenge to <@%v>.", v.Challenger, v.Target)
+}
+
+func ephemeralResponse(content string) InteractionResponse {
+	return InteractionResponse{
+		Type: ResponseTypeChannelMessage,
+		Data: &ResponseData{
+			Content: content,
+			Flags:   MessageFlagEphemeral,
+		},
+	}
+}
+
+func writeResponse(w http.ResponseWriter, response InteractionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.Error("Failed to write the interaction response", "error", err)
+	}
+}
@@ -0,0 +1,212 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+const timestamp = "1700000000"
+
+func TestRejectsInvalidSignatures(t *testing.T) {
+	gameServer, _ := createMockServer(t)
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	r := signedRequest(otherKey, `{"type": 1}`)
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status %v, got %v", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRespondsToPing(t *testing.T) {
+	gameServer, privateKey := createMockServer(t)
+	response := doInteraction(t, gameServer, privateKey, Interaction{Type: InteractionTypePing})
+
+	if response.Type != ResponseTypePong {
+		t.Fatalf("Expected a pong, got %+v", response)
+	}
+}
+
+func TestPlaysAGame(t *testing.T) {
+	gameServer, privateKey := createMockServer(t)
+	response := doInteraction(t, gameServer, privateKey, challengeInteraction("bob"))
+
+	if response.Type != ResponseTypeChannelMessage || response.Data.Flags&MessageFlagEphemeral != 0 {
+		t.Fatalf("Expected a public challenge message, got %+v", response)
+	}
+
+	buttons := response.Data.Components[0].Components
+	if len(buttons) != len(modes.StandardGame.PreferredOrder) || buttons[0].Label != "Rock" {
+		t.Fatalf("Unexpected move buttons: %+v", buttons)
+	}
+
+	response = doInteraction(t, gameServer, privateKey, componentInteraction("alice", buttons[0].CustomID))
+	if response.Data.Content != "Your move has been locked in" || response.Data.Flags&MessageFlagEphemeral == 0 {
+		t.Fatalf("Expected an ephemeral confirmation, got %+v", response)
+	}
+
+	response = doInteraction(t, gameServer, privateKey, componentInteraction("eve", buttons[1].CustomID))
+	if !strings.Contains(response.Data.Content, "Only the players") {
+		t.Fatalf("Expected a non player to be rejected, got %+v", response)
+	}
+
+	response = doInteraction(t, gameServer, privateKey, componentInteraction("bob", buttons[2].CustomID))
+	if response.Type != ResponseTypeUpdateMessage || len(response.Data.Components) != 0 {
+		t.Fatalf("Expected the challenge to be replaced by the result, got %+v", response)
+	}
+
+	if response.Data.Content != "<@alice> defeated <@bob>, rock beats scissors" {
+		t.Fatalf("Unexpected result: %v", response.Data.Content)
+	}
+}
+
+func TestRejectsSelfChallenges(t *testing.T) {
+	gameServer, privateKey := createMockServer(t)
+	response := doInteraction(t, gameServer, privateKey, challengeInteraction("alice"))
+
+	if response.Data.Flags&MessageFlagEphemeral == 0 || len(gameServer.GameSessionsManager.GameSessions) != 0 {
+		t.Fatalf("Expected the challenge to be rejected, got %+v", response)
+	}
+}
+
+func TestCancelButtonWithdrawsChallenge(t *testing.T) {
+	gameServer, privateKey := createMockServer(t)
+	response := doInteraction(t, gameServer, privateKey, challengeInteraction("bob"))
+
+	rows := response.Data.Components
+	cancel := rows[len(rows)-1].Components[0]
+	if cancel.Label != "Cancel" {
+		t.Fatalf("Expected the challenge to have a Cancel button, got %+v", rows)
+	}
+
+	response = doInteraction(t, gameServer, privateKey, componentInteraction("bob", cancel.CustomID))
+	if response.Data.Content != server.ErrNotSessionChallenger.Error() || response.Data.Flags&MessageFlagEphemeral == 0 {
+		t.Fatalf("Expected the target to be unable to withdraw the challenge, got %+v", response)
+	}
+
+	response = doInteraction(t, gameServer, privateKey, componentInteraction("alice", cancel.CustomID))
+	if response.Type != ResponseTypeUpdateMessage || len(response.Data.Components) != 0 || !strings.HasPrefix(response.Data.Content, "Challenge withdrawn") {
+		t.Fatalf("Expected the buttons to be replaced with the withdrawn challenge, got %+v", response)
+	}
+	if len(gameServer.GameSessionsManager.Sessions()) != 0 {
+		t.Fatal("Expected the session to be removed")
+	}
+}
+
+func TestCancelCommandWithdrawsChallenge(t *testing.T) {
+	edits := make(chan *http.Request, 1)
+	discordAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		edits <- r
+	}))
+	defer discordAPI.Close()
+
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	gameServer := server.NewGameServer(modes.StandardGame)
+	registerRoutes(gameServer, publicKey, discordAPI.URL)
+
+	challenge := challengeInteraction("bob")
+	challenge.ApplicationID, challenge.Token = "app", "token"
+	doInteraction(t, gameServer, privateKey, challenge)
+
+	for _, v := range gameServer.GameSessionsManager.Sessions() {
+		for _, value := range v.Data {
+			if value == challenge.Token {
+				t.Fatalf("Expected the interaction token to be kept out of the session data, got %v", v.Data)
+			}
+		}
+	}
+
+	cancel := challengeInteraction("bob")
+	cancel.Data.Options = []CommandOption{{Name: CancelSubcommand, Type: CommandOptionTypeSubCommand}}
+
+	response := doInteraction(t, gameServer, privateKey, cancel)
+	if response.Type != ResponseTypeChannelMessage || response.Data.AllowedMentions.Users[0] != "bob" {
+		t.Fatalf("Expected the target to be notified, got %+v", response)
+	}
+
+	select {
+	case edit := <-edits:
+		if edit.Method != "PATCH" || edit.URL.Path != "/webhooks/app/token/messages/@original" {
+			t.Fatalf("Expected the challenge message to be edited, got %v %v", edit.Method, edit.URL.Path)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the challenge message to be edited")
+	}
+
+	response = doInteraction(t, gameServer, privateKey, cancel)
+	if response.Data.Content != "You have no pending challenge to withdraw in this channel." {
+		t.Fatalf("Expected no challenge to be left, got %+v", response)
+	}
+}
+
+func createMockServer(t *testing.T) (*server.GameServer, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate a key: %q", err)
+	}
+
+	gameServer := server.NewGameServer(modes.StandardGame)
+	RegisterRoutes(gameServer, publicKey)
+
+	return gameServer, privateKey
+}
+
+func signedRequest(privateKey ed25519.PrivateKey, body string) *http.Request {
+	r := httptest.NewRequest("POST", HandleInteractionRoute, strings.NewReader(body))
+	r.Header.Set(timestampHeader, timestamp)
+	r.Header.Set(signatureHeader, hex.EncodeToString(ed25519.Sign(privateKey, []byte(timestamp+body))))
+
+	return r
+}
+
+func doInteraction(t *testing.T, gameServer *server.GameServer, privateKey ed25519.PrivateKey, interaction Interaction) InteractionResponse {
+	var response InteractionResponse
+
+	body, _ := json.Marshal(interaction)
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, signedRequest(privateKey, string(body)))
+
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid interaction response %q: %q", w.Body.String(), err)
+	}
+
+	return response
+}
+
+func challengeInteraction(target string) Interaction {
+	return Interaction{
+		Type:      InteractionTypeApplicationCommand,
+		ChannelID: "general",
+		Member:    &Member{User: &User{ID: "alice", Username: "alice"}},
+		Data: InteractionData{
+			Name: CommandName,
+			Options: []CommandOption{{
+				Name:    ChallengeSubcommand,
+				Type:    CommandOptionTypeSubCommand,
+				Options: []CommandOption{{Name: OpponentOption, Type: CommandOptionTypeUser, Value: target}},
+			}},
+			Resolved: Resolved{Users: map[string]User{
+				target: {ID: target, Username: target},
+			}},
+		},
+	}
+}
+
+func componentInteraction(user, customID string) Interaction {
+	return Interaction{
+		Type:      InteractionTypeMessageComponent,
+		ChannelID: "general",
+		Member:    &Member{User: &User{ID: user, Username: user}},
+		Data:      InteractionData{CustomID: customID},
+	}
+}
@@ -0,0 +1,37 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"net/http"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/server"
+)
+
+const (
+	// HandleInteractionRoute defines the route used by the HandleInteraction controller method.
+	// It should be configured as the application's Interactions Endpoint URL.
+	HandleInteractionRoute = "/discord/interactions"
+	// DefaultAPIURL is the base URL of Discord's HTTP API, used for editing messages.
+	DefaultAPIURL = "https://discord.com/api/v10"
+)
+
+// RegisterRoutes registers the Discord routes on the GameServer's ServeMux.
+// The publicKey is the application's public key, used for verifying that requests were sent by Discord.
+func RegisterRoutes(gameServer *server.GameServer, publicKey ed25519.PublicKey) {
+	registerRoutes(gameServer, publicKey, DefaultAPIURL)
+}
+
+func registerRoutes(gameServer *server.GameServer, publicKey ed25519.PublicKey, apiURL string) {
+	adapter := &adapter{publicKey: publicKey}
+	controller := &controller{
+		Service:    chat.NewService(gameServer, adapter),
+		adapter:    adapter,
+		apiURL:     apiURL,
+		httpClient: &http.Client{Timeout: apiTimeout},
+		messages:   make(map[string]originalMessage),
+	}
+	gameServer.GameSessionsManager.Events.Listen(controller.handleEvent)
+
+	gameServer.ServeMux.HandleFunc(HandleInteractionRoute, controller.HandleInteraction)
+}
@@ -0,0 +1,155 @@
+// Package bot provides computer controlled strategies for playing any game.Game.
+package bot
+
+import (
+	"github.com/hamologist/rps/game"
+)
+
+// Round records the moves made by a strategy and its opponent in a single round.
+type Round struct {
+	Move         string
+	OpponentMove string
+}
+
+// Strategy picks the next move to play given the rounds played so far.
+type Strategy interface {
+	Move(history []Round) string
+}
+
+// Factory creates a fresh Strategy for the provided game.
+type Factory func(g game.Game, source game.RandomSource) Strategy
+
+// RegisteredStrategies defines all available bot strategies.
+var RegisteredStrategies = map[string]Factory{
+	"random":       NewRandom,
+	"constant":     NewConstant,
+	"cycle":        NewCycle,
+	"mirror":       NewMirror,
+	"counter-last": NewCounterLast,
+	"frequency":    NewFrequency,
+}
+
+type random struct {
+	game   game.Game
+	source game.RandomSource
+}
+
+// NewRandom creates a Strategy that picks a move uniformly at random.
+func NewRandom(g game.Game, source game.RandomSource) Strategy {
+	return &random{game: g, source: source}
+}
+
+func (strategy *random) Move(history []Round) string {
+	return strategy.game.RandomMove(strategy.source)
+}
+
+type constant struct {
+	move string
+}
+
+// NewConstant creates a Strategy that always plays the first move in the game's PreferredOrder.
+func NewConstant(g game.Game, source game.RandomSource) Strategy {
+	return &constant{move: g.PreferredOrder[0]}
+}
+
+func (strategy *constant) Move(history []Round) string {
+	return strategy.move
+}
+
+type cycle struct {
+	game game.Game
+}
+
+// NewCycle creates a Strategy that walks through the game's PreferredOrder one move per round.
+func NewCycle(g game.Game, source game.RandomSource) Strategy {
+	return &cycle{game: g}
+}
+
+func (strategy *cycle) Move(history []Round) string {
+	order := strategy.game.PreferredOrder
+	return order[len(history)%len(order)]
+}
+
+type mirror struct {
+	game   game.Game
+	source game.RandomSource
+}
+
+// NewMirror creates a Strategy that repeats the opponent's previous move.
+func NewMirror(g game.Game, source game.RandomSource) Strategy {
+	return &mirror{game: g, source: source}
+}
+
+func (strategy *mirror) Move(history []Round) string {
+	if len(history) == 0 {
+		return strategy.game.RandomMove(strategy.source)
+	}
+
+	return history[len(history)-1].OpponentMove
+}
+
+type counterLast struct {
+	game   game.Game
+	source game.RandomSource
+}
+
+// NewCounterLast creates a Strategy that plays a move defeating the opponent's previous move.
+func NewCounterLast(g game.Game, source game.RandomSource) Strategy {
+	return &counterLast{game: g, source: source}
+}
+
+func (strategy *counterLast) Move(history []Round) string {
+	if len(history) == 0 {
+		return strategy.game.RandomMove(strategy.source)
+	}
+
+	return counter(strategy.game, strategy.source, history[len(history)-1].OpponentMove)
+}
+
+type frequency struct {
+	game   game.Game
+	source game.RandomSource
+}
+
+// NewFrequency creates a Strategy that plays a move defeating the opponent's most frequent move.
+// Ties between equally frequent moves are broken at random.
+func NewFrequency(g game.Game, source game.RandomSource) Strategy {
+	return &frequency{game: g, source: source}
+}
+
+func (strategy *frequency) Move(history []Round) string {
+	if len(history) == 0 {
+		return strategy.game.RandomMove(strategy.source)
+	}
+
+	var (
+		counts   = make(map[string]int)
+		best     int
+		frequent []string
+	)
+
+	for _, round := range history {
+		counts[round.OpponentMove]++
+	}
+
+	for _, move := range strategy.game.PreferredOrder {
+		if counts[move] > best {
+			best = counts[move]
+			frequent = []string{move}
+		} else if counts[move] == best && best > 0 {
+			frequent = append(frequent, move)
+		}
+	}
+
+	return counter(strategy.game, strategy.source, frequent[strategy.source.Intn(len(frequent))])
+}
+
+func counter(g game.Game, source game.RandomSource, move string) string {
+	counters := g.Counters(move)
+
+	if len(counters) == 0 {
+		return g.RandomMove(source)
+	}
+
+	return counters[source.Intn(len(counters))]
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+)
+
+func TestRegisteredStrategiesPlayValidMoves(t *testing.T) {
+	history := []Round{{Move: "rock", OpponentMove: "paper"}}
+
+	for name, factory := range RegisteredStrategies {
+		strategy := factory(modes.StandardGame, game.NewSeededSource(1))
+
+		for _, h := range [][]Round{nil, history} {
+			move := strategy.Move(h)
+			if _, ok := modes.StandardGame.Moves[move]; !ok {
+				t.Fatalf("%v played an invalid move: %q", name, move)
+			}
+		}
+	}
+}
+
+func TestCounterLastDefeatsPreviousMove(t *testing.T) {
+	strategy := NewCounterLast(modes.StandardGame, game.NewSeededSource(1))
+	move := strategy.Move([]Round{{Move: "rock", OpponentMove: "rock"}})
+
+	if move != "paper" {
+		t.Fatalf("counter-last should have countered rock with paper, played %q", move)
+	}
+}
+
+func TestFrequencyCountersMostFrequentMove(t *testing.T) {
+	strategy := NewFrequency(modes.StandardGame, game.NewSeededSource(1))
+	move := strategy.Move([]Round{
+		{OpponentMove: "scissors"},
+		{OpponentMove: "paper"},
+		{OpponentMove: "scissors"},
+	})
+
+	if move != "rock" {
+		t.Fatalf("frequency should have countered scissors with rock, played %q", move)
+	}
+}
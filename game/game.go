@@ -2,6 +2,8 @@ package game
 
 import (
 	"fmt"
+	"unicode"
+	"unicode/utf8"
 )
 
 // GameStatePlayerOneWins state for when player one wins.
@@ -52,3 +54,29 @@ func (game *Game) Play(playerOneMove string, playerTwoMove string) (string, erro
 
 	return GameStatePlayerTwoWins, nil
 }
+
+// Counters returns the moves that defeat the provided move, following the game's PreferredOrder.
+func (game *Game) Counters(move string) []string {
+	var counters []string
+
+	for _, name := range game.PreferredOrder {
+		for _, v := range game.Moves[name].Defeats {
+			if v == move {
+				counters = append(counters, name)
+				break
+			}
+		}
+	}
+
+	return counters
+}
+
+// DisplayName returns the name of a move (or mode) capitalized for displaying it to the user, e.g. "Rock".
+func DisplayName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError {
+		return name
+	}
+
+	return string(unicode.ToUpper(r)) + name[size:]
+}
@@ -73,6 +73,27 @@ func TestGameEndsInDraw(t *testing.T) {
 	}
 }
 
+func TestCountersReturnsDefeatingMoves(t *testing.T) {
+	game := createMockGame()
+	counters := game.Counters(losingMove)
+
+	if len(counters) != 1 || counters[0] != winningMove {
+		t.Fatalf("Expected %q to be countered by %q, got %q", losingMove, winningMove, counters)
+	}
+
+	if counters := game.Counters(winningMove); len(counters) != 0 {
+		t.Fatalf("Expected %q to have no counters, got %q", winningMove, counters)
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	for name, expected := range map[string]string{"rock": "Rock", "rpsls": "Rpsls", "": ""} {
+		if displayName := DisplayName(name); displayName != expected {
+			t.Fatalf("Expected %q to be displayed as %q, got %q", name, expected, displayName)
+		}
+	}
+}
+
 func createMockGame() Game {
 	return Game{
 		Moves: map[string]Move{
@@ -1,10 +1,11 @@
 package modes
 
 import (
-	"bitbucket.org/hamologist/rps/game"
+	"github.com/hamologist/rps/game"
 )
 
 // RegisteredGames defines all available game modes.
 var RegisteredGames = map[string]game.Game{
 	"standard": StandardGame,
+	"rpsls":    RPSLSGame,
 }
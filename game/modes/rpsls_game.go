@@ -0,0 +1,35 @@
+package modes
+
+import (
+	"github.com/hamologist/rps/game"
+)
+
+const lizard = "lizard"
+const spock = "spock"
+
+// RPSLSGame defines the rock, paper, scissors, lizard, Spock variant of RPS
+var RPSLSGame = game.Game{
+	Moves: map[string]game.Move{
+		rock: game.Move{
+			Name:    rock,
+			Defeats: []string{scissors, lizard},
+		},
+		paper: game.Move{
+			Name:    paper,
+			Defeats: []string{rock, spock},
+		},
+		scissors: game.Move{
+			Name:    scissors,
+			Defeats: []string{paper, lizard},
+		},
+		lizard: game.Move{
+			Name:    lizard,
+			Defeats: []string{spock, paper},
+		},
+		spock: game.Move{
+			Name:    spock,
+			Defeats: []string{scissors, rock},
+		},
+	},
+	PreferredOrder: []string{rock, paper, scissors, lizard, spock},
+}
@@ -0,0 +1,50 @@
+package modes
+
+import (
+	"github.com/hamologist/rps/game"
+	"testing"
+)
+
+func TestRPSLSMovesDefeatTwoOthers(t *testing.T) {
+	for _, playerOneMove := range RPSLSGame.PreferredOrder {
+		wins := 0
+		for _, playerTwoMove := range RPSLSGame.PreferredOrder {
+			result, err := RPSLSGame.Play(playerOneMove, playerTwoMove)
+			if err != nil {
+				t.Fatalf("Game state should not have caused an error: %q", err)
+			}
+
+			if result == game.GameStatePlayerOneWins {
+				wins++
+			}
+		}
+
+		if wins != 2 {
+			t.Fatalf("%v should defeat exactly two moves, defeated %v", playerOneMove, wins)
+		}
+	}
+}
+
+func TestSpockVaporizesRock(t *testing.T) {
+	result, err := RPSLSGame.Play(spock, rock)
+
+	if result != game.GameStatePlayerOneWins {
+		t.Fatal("Spock failed to vaporize rock")
+	}
+
+	if err != nil {
+		t.Fatalf("Game state should not have caused an error: %q", err)
+	}
+}
+
+func TestLizardEatsPaper(t *testing.T) {
+	result, err := RPSLSGame.Play(paper, lizard)
+
+	if result != game.GameStatePlayerTwoWins {
+		t.Fatal("Lizard failed to eat paper")
+	}
+
+	if err != nil {
+		t.Fatalf("Game state should not have caused an error: %q", err)
+	}
+}
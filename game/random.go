@@ -0,0 +1,57 @@
+package game
+
+import (
+	cryptorand "crypto/rand"
+	"math/big"
+	"math/rand"
+	"sync"
+)
+
+// RandomSource is used for any random choice made on behalf of a player (bot moves, tie-breaks, etc.).
+type RandomSource interface {
+	// Intn returns a random number in [0, n). Intn panics if n <= 0.
+	Intn(n int) int
+}
+
+// DefaultRandomSource is the RandomSource used when no other source is provided.
+var DefaultRandomSource RandomSource = CryptoSource{}
+
+// CryptoSource is a RandomSource backed by crypto/rand.
+type CryptoSource struct{}
+
+// Intn returns a cryptographically secure random number in [0, n).
+func (CryptoSource) Intn(n int) int {
+	if n <= 0 {
+		panic("game: invalid argument to Intn")
+	}
+
+	v, err := cryptorand.Int(cryptorand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(err)
+	}
+
+	return int(v.Int64())
+}
+
+type seededSource struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+// NewSeededSource creates a deterministic RandomSource.
+// Intended for tests and replays, it should not be used for games played against real users.
+func NewSeededSource(seed int64) RandomSource {
+	return &seededSource{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (source *seededSource) Intn(n int) int {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	return source.rand.Intn(n)
+}
+
+// RandomMove picks one of the game's moves using the provided RandomSource.
+func (game *Game) RandomMove(source RandomSource) string {
+	return game.PreferredOrder[source.Intn(len(game.PreferredOrder))]
+}
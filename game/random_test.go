@@ -0,0 +1,37 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestSeededSourceIsDeterministic(t *testing.T) {
+	first := NewSeededSource(7)
+	second := NewSeededSource(7)
+
+	for i := 0; i < 100; i++ {
+		if first.Intn(1000) != second.Intn(1000) {
+			t.Fatal("Seeded sources with the same seed diverged")
+		}
+	}
+}
+
+func TestCryptoSourceStaysInRange(t *testing.T) {
+	source := CryptoSource{}
+
+	for i := 0; i < 100; i++ {
+		if v := source.Intn(3); v < 0 || v >= 3 {
+			t.Fatalf("CryptoSource returned a value out of range: %v", v)
+		}
+	}
+}
+
+func TestRandomMoveReturnsValidMove(t *testing.T) {
+	game := createMockGame()
+
+	for i := 0; i < 100; i++ {
+		move := game.RandomMove(NewSeededSource(int64(i)))
+		if _, ok := game.Moves[move]; !ok {
+			t.Fatalf("RandomMove returned an invalid move: %q", move)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package tournament
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+type jsonRecord struct {
+	Record
+	Games   int     `json:"games"`
+	WinRate float64 `json:"win_rate"`
+	CILow   float64 `json:"ci_low"`
+	CIHigh  float64 `json:"ci_high"`
+}
+
+type jsonStanding struct {
+	Strategy  string                `json:"strategy"`
+	Total     jsonRecord            `json:"total"`
+	Opponents map[string]jsonRecord `json:"opponents"`
+}
+
+type jsonResult struct {
+	Rounds    int            `json:"rounds"`
+	Standings []jsonStanding `json:"standings"`
+}
+
+// WriteJSON writes the Result, including win rates and confidence intervals, as JSON.
+func (result *Result) WriteJSON(w io.Writer) error {
+	report := jsonResult{Rounds: result.Rounds}
+
+	for _, standing := range result.Standings {
+		s := jsonStanding{
+			Strategy:  standing.Strategy,
+			Total:     newJSONRecord(standing.Total),
+			Opponents: make(map[string]jsonRecord),
+		}
+
+		for opponent, record := range standing.Opponents {
+			s.Opponents[opponent] = newJSONRecord(record)
+		}
+
+		report.Standings = append(report.Standings, s)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+// WriteCSV writes the Result as CSV.
+// Each row is a strategy's overall record followed by a cross-table of its win rate against every strategy.
+func (result *Result) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"strategy", "games", "wins", "losses", "draws", "win_rate", "ci_low", "ci_high"}
+
+	for _, standing := range result.Standings {
+		header = append(header, "vs_"+standing.Strategy)
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, standing := range result.Standings {
+		low, high := standing.Total.ConfidenceInterval()
+		row := []string{
+			standing.Strategy,
+			strconv.Itoa(standing.Total.Games()),
+			strconv.Itoa(standing.Total.Wins),
+			strconv.Itoa(standing.Total.Losses),
+			strconv.Itoa(standing.Total.Draws),
+			formatRate(standing.Total.WinRate()),
+			formatRate(low),
+			formatRate(high),
+		}
+
+		for _, opponent := range result.Standings {
+			if record, ok := standing.Opponents[opponent.Strategy]; ok {
+				row = append(row, formatRate(record.WinRate()))
+			} else {
+				row = append(row, "")
+			}
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func newJSONRecord(record Record) jsonRecord {
+	low, high := record.ConfidenceInterval()
+
+	return jsonRecord{
+		Record:  record,
+		Games:   record.Games(),
+		WinRate: record.WinRate(),
+		CILow:   low,
+		CIHigh:  high,
+	}
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 4, 64)
+}
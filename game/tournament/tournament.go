@@ -0,0 +1,164 @@
+// Package tournament provides a headless harness for pitting bot strategies against each other.
+package tournament
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/bot"
+)
+
+// confidenceZ is the z-score used for the 95% confidence intervals reported by a tournament.
+const confidenceZ = 1.96
+
+// Config defines the parameters of a tournament.
+type Config struct {
+	Game       game.Game
+	Rounds     int      // The number of rounds played by each pairing of strategies.
+	Strategies []string // Names of strategies registered in bot.RegisteredStrategies.
+	Seed       int64    // Seeds every strategy's RandomSource so tournaments can be replayed.
+}
+
+// Record tracks the outcomes a strategy has collected.
+type Record struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+// Games returns the total number of rounds in the Record.
+func (record Record) Games() int {
+	return record.Wins + record.Losses + record.Draws
+}
+
+// WinRate returns the fraction of rounds won.
+func (record Record) WinRate() float64 {
+	if record.Games() == 0 {
+		return 0
+	}
+
+	return float64(record.Wins) / float64(record.Games())
+}
+
+// ConfidenceInterval returns the 95% Wilson score interval for the Record's WinRate.
+func (record Record) ConfidenceInterval() (float64, float64) {
+	n := float64(record.Games())
+	if n == 0 {
+		return 0, 0
+	}
+
+	p := record.WinRate()
+	z2 := confidenceZ * confidenceZ
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := confidenceZ * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / (1 + z2/n)
+
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// Standing summarizes how a single strategy performed across the tournament.
+type Standing struct {
+	Strategy  string            `json:"strategy"`
+	Total     Record            `json:"total"`
+	Opponents map[string]Record `json:"opponents"`
+}
+
+// Result holds the outcome of a tournament.
+// Standings follow the order strategies were provided in the Config.
+type Result struct {
+	Rounds    int        `json:"rounds"`
+	Standings []Standing `json:"standings"`
+}
+
+// Run plays every pairing of the configured strategies against each other.
+func Run(config Config) (*Result, error) {
+	if config.Rounds <= 0 {
+		return nil, fmt.Errorf("Rounds must be positive: %v", config.Rounds)
+	}
+
+	if len(config.Strategies) < 2 {
+		return nil, fmt.Errorf("At least two strategies are needed for a tournament")
+	}
+
+	entered := make(map[string]bool)
+	for _, name := range config.Strategies {
+		if _, ok := bot.RegisteredStrategies[name]; !ok {
+			return nil, fmt.Errorf("Unknown strategy: %v", name)
+		}
+		if entered[name] {
+			return nil, fmt.Errorf("Strategy entered more than once: %v", name)
+		}
+		entered[name] = true
+	}
+
+	seeds := rand.New(rand.NewSource(config.Seed))
+	standings := make([]Standing, len(config.Strategies))
+	for i, name := range config.Strategies {
+		standings[i] = Standing{Strategy: name, Opponents: make(map[string]Record)}
+	}
+
+	for i := range standings {
+		for j := i + 1; j < len(standings); j++ {
+			record, err := playMatch(config, standings[i].Strategy, standings[j].Strategy, seeds)
+			if err != nil {
+				return nil, err
+			}
+
+			standings[i].Opponents[standings[j].Strategy] = record
+			standings[j].Opponents[standings[i].Strategy] = Record{
+				Wins:   record.Losses,
+				Losses: record.Wins,
+				Draws:  record.Draws,
+			}
+		}
+	}
+
+	for i := range standings {
+		for _, record := range standings[i].Opponents {
+			standings[i].Total.Wins += record.Wins
+			standings[i].Total.Losses += record.Losses
+			standings[i].Total.Draws += record.Draws
+		}
+	}
+
+	return &Result{
+		Rounds:    config.Rounds,
+		Standings: standings,
+	}, nil
+}
+
+// playMatch plays config.Rounds rounds between two strategies and returns the first strategy's Record.
+func playMatch(config Config, playerOne, playerTwo string, seeds *rand.Rand) (Record, error) {
+	var (
+		record     Record
+		historyOne []bot.Round
+		historyTwo []bot.Round
+	)
+	strategyOne := bot.RegisteredStrategies[playerOne](config.Game, game.NewSeededSource(seeds.Int63()))
+	strategyTwo := bot.RegisteredStrategies[playerTwo](config.Game, game.NewSeededSource(seeds.Int63()))
+
+	for round := 0; round < config.Rounds; round++ {
+		moveOne := strategyOne.Move(historyOne)
+		moveTwo := strategyTwo.Move(historyTwo)
+
+		result, err := config.Game.Play(moveOne, moveTwo)
+		if err != nil {
+			return record, err
+		}
+
+		switch result {
+		case game.GameStatePlayerOneWins:
+			record.Wins++
+		case game.GameStatePlayerTwoWins:
+			record.Losses++
+		default:
+			record.Draws++
+		}
+
+		historyOne = append(historyOne, bot.Round{Move: moveOne, OpponentMove: moveTwo})
+		historyTwo = append(historyTwo, bot.Round{Move: moveTwo, OpponentMove: moveOne})
+	}
+
+	return record, nil
+}
@@ -0,0 +1,128 @@
+package tournament
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/hamologist/rps/game/modes"
+)
+
+func TestRunRecordsEveryRound(t *testing.T) {
+	result, err := Run(createMockConfig())
+
+	if err != nil {
+		t.Fatalf("Tournament should not have caused an error: %q", err)
+	}
+
+	for _, standing := range result.Standings {
+		if standing.Total.Games() != 200 {
+			t.Fatalf("%v should have played 200 rounds, played %v", standing.Strategy, standing.Total.Games())
+		}
+	}
+}
+
+func TestRunIsSymmetric(t *testing.T) {
+	result, _ := Run(createMockConfig())
+	constant := result.Standings[0].Opponents["counter-last"]
+	counterLast := result.Standings[1].Opponents["constant"]
+
+	if constant.Wins != counterLast.Losses || constant.Losses != counterLast.Wins || constant.Draws != counterLast.Draws {
+		t.Fatalf("Cross-table records do not mirror each other: %v %v", constant, counterLast)
+	}
+}
+
+func TestRunIsDeterministicForASeed(t *testing.T) {
+	first, _ := Run(createMockConfig())
+	second, _ := Run(createMockConfig())
+
+	for i := range first.Standings {
+		if first.Standings[i].Total != second.Standings[i].Total {
+			t.Fatalf("Tournament with the same seed produced different results for %v", first.Standings[i].Strategy)
+		}
+	}
+}
+
+func TestCounterLastBeatsConstant(t *testing.T) {
+	result, _ := Run(createMockConfig())
+	record := result.Standings[1].Opponents["constant"]
+
+	if record.WinRate() < 0.9 {
+		t.Fatalf("counter-last should exploit constant, win rate was %v", record.WinRate())
+	}
+}
+
+func TestRunRejectsUnknownStrategies(t *testing.T) {
+	config := createMockConfig()
+	config.Strategies = append(config.Strategies, "unknown")
+
+	if _, err := Run(config); err == nil {
+		t.Fatal("Tournament should have rejected an unknown strategy")
+	}
+}
+
+func TestRunRejectsDuplicateStrategies(t *testing.T) {
+	config := createMockConfig()
+	config.Strategies = append(config.Strategies, config.Strategies[0])
+
+	if _, err := Run(config); err == nil {
+		t.Fatal("Tournament should have rejected a strategy entered twice")
+	}
+}
+
+func TestConfidenceIntervalContainsWinRate(t *testing.T) {
+	record := Record{Wins: 30, Losses: 50, Draws: 20}
+	low, high := record.ConfidenceInterval()
+
+	if low > record.WinRate() || high < record.WinRate() || low < 0 || high > 1 {
+		t.Fatalf("Confidence interval (%v, %v) does not contain win rate %v", low, high, record.WinRate())
+	}
+}
+
+func TestWriteCSVIncludesCrossTable(t *testing.T) {
+	var buf bytes.Buffer
+	result, _ := Run(createMockConfig())
+
+	if err := result.WriteCSV(&buf); err != nil {
+		t.Fatalf("Writing CSV should not have caused an error: %q", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Tournament produced invalid CSV: %q", err)
+	}
+
+	if len(rows) != 4 || len(rows[0]) != 11 {
+		t.Fatalf("Unexpected CSV dimensions: %v rows, %v columns", len(rows), len(rows[0]))
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		report jsonResult
+	)
+	result, _ := Run(createMockConfig())
+
+	if err := result.WriteJSON(&buf); err != nil {
+		t.Fatalf("Writing JSON should not have caused an error: %q", err)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Tournament produced invalid JSON: %q", err)
+	}
+
+	if len(report.Standings) != 3 || report.Standings[0].Total.Games != 200 {
+		t.Fatalf("Unexpected JSON report: %+v", report)
+	}
+}
+
+func createMockConfig() Config {
+	return Config{
+		Game:       modes.StandardGame,
+		Rounds:     100,
+		Strategies: []string{"constant", "counter-last", "random"},
+		Seed:       42,
+	}
+}
@@ -0,0 +1,162 @@
+// Package logging sets up structured logging through log/slog.
+// Records carry the attributes stored in their context, such as the request ID, and secrets are redacted from every record.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hamologist/rps/metrics"
+)
+
+// Keys of the attributes identifying what a record is about.
+const (
+	RequestIDKey = "request_id"
+	TeamKey      = "team"
+	UserKey      = "user"
+	SessionKey   = "session"
+)
+
+// RequestIDHeader is the header a request ID is read from, and written to in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// Redacted replaces the values of secrets.
+const Redacted = "[REDACTED]"
+
+var (
+	// sensitiveKeys are the parts of attribute keys whose values are always redacted.
+	// The OAuth "code" is matched exactly instead.
+	sensitiveKeys = []string{"token", "secret", "signature", "authorization", "password", "response_url"}
+	// sensitiveValues match secrets found in values, such as Slack tokens or the token field of a JSON payload.
+	sensitiveValues = []*regexp.Regexp{
+		regexp.MustCompile(`xox[a-z]-[A-Za-z0-9-]+`),
+		regexp.MustCompile(`("(?:token|access_token|client_secret|response_url)"\s*:\s*")[^"]*`),
+	}
+)
+
+type contextKey struct{}
+
+// New creates a logger writing JSON records to w, which logs debug records when debug is set.
+func New(w io.Writer, debug bool) *slog.Logger {
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level, ReplaceAttr: redact})})
+}
+
+// With returns a copy of ctx carrying the attributes, which are added to every record logged with the context.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	current := attrsFrom(ctx)
+	return context.WithValue(ctx, contextKey{}, append(current[:len(current):len(current)], attrs...))
+}
+
+// Detach returns a background context carrying only the attributes of ctx, for work outliving the request ctx belongs to.
+// The request's cancellation and values are left behind, so they can't leak into the work.
+func Detach(ctx context.Context) context.Context {
+	return With(context.Background(), attrsFrom(ctx)...)
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// Values logs form or query values as a group, so each value is redacted based on its key.
+func Values(values url.Values) slog.Value {
+	var attrs []slog.Attr
+
+	for key, value := range values {
+		attrs = append(attrs, slog.String(key, strings.Join(value, ",")))
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+
+	return slog.GroupValue(attrs...)
+}
+
+// contextHandler adds the attributes stored in the context to every record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (handler contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	record.AddAttrs(attrsFrom(ctx)...)
+	return handler.Handler.Handle(ctx, record)
+}
+
+func (handler contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{handler.Handler.WithAttrs(attrs)}
+}
+
+func (handler contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{handler.Handler.WithGroup(name)}
+}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	if key == "code" {
+		return slog.String(attr.Key, Redacted)
+	}
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(key, sensitiveKey) {
+			return slog.String(attr.Key, Redacted)
+		}
+	}
+
+	if attr.Value.Kind() == slog.KindString {
+		return slog.String(attr.Key, RedactString(attr.Value.String()))
+	}
+	if err, ok := attr.Value.Any().(error); ok {
+		return slog.String(attr.Key, RedactString(err.Error()))
+	}
+
+	return attr
+}
+
+// RedactString replaces the secrets found in s.
+func RedactString(s string) string {
+	s = sensitiveValues[0].ReplaceAllString(s, Redacted)
+	return sensitiveValues[1].ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// Middleware assigns every request an ID, kept from the X-Request-ID header when the client sent one,
+// and logs each request once it was handled.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := With(r.Context(), slog.String(RequestIDKey, requestID))
+		recorder := metrics.NewStatusRecorder(w)
+		started := time.Now()
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		slog.InfoContext(ctx, "Handled request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.Status,
+			"duration", time.Since(started),
+		)
+	})
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
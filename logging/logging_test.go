@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func decodeRecord(t *testing.T, buffer *bytes.Buffer) map[string]interface{} {
+	var record map[string]interface{}
+
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q", buffer.String())
+	}
+
+	return record
+}
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New(&buffer, false)
+
+	logger.Info("Received a request",
+		"form", Values(url.Values{"token": {"verification"}, "text": {"<@U2>"}, "payload": {`{"token":"abc","user":"U1"}`}}),
+		"error", errors.New("invalid token xoxb-123-456"),
+		"code", "oauth-code",
+	)
+
+	output := buffer.String()
+	for _, secret := range []string{"verification", "abc", "xoxb-123-456", "oauth-code"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("Expected %q to be redacted from %v", secret, output)
+		}
+	}
+
+	form := decodeRecord(t, &buffer)["form"].(map[string]interface{})
+	if form["text"] != "<@U2>" || form["token"] != Redacted || form["payload"] != `{"token":"[REDACTED]","user":"U1"}` {
+		t.Fatalf("Unexpected form: %v", form)
+	}
+}
+
+func TestLoggerAddsContextAttributes(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New(&buffer, true)
+
+	ctx := With(context.Background(), slog.String(RequestIDKey, "abc"))
+	ctx = With(ctx, slog.String(TeamKey, "T1"), slog.String(UserKey, "U1"))
+	logger.DebugContext(ctx, "Handled", SessionKey, "session")
+
+	record := decodeRecord(t, &buffer)
+	if record[RequestIDKey] != "abc" || record[TeamKey] != "T1" || record[UserKey] != "U1" || record[SessionKey] != "session" {
+		t.Fatalf("Expected the context's attributes to be logged, got %v", record)
+	}
+
+	buffer.Reset()
+	New(&buffer, false).DebugContext(ctx, "Hidden")
+	if buffer.Len() != 0 {
+		t.Fatalf("Expected debug records to be dropped, got %q", buffer.String())
+	}
+}
+
+func TestDetachKeepsOnlyAttributes(t *testing.T) {
+	var buffer bytes.Buffer
+
+	ctx, cancel := context.WithCancel(With(context.Background(), slog.String(RequestIDKey, "abc")))
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil {
+		t.Fatal("Expected the detached context not to be cancelled with the request")
+	}
+
+	New(&buffer, true).DebugContext(detached, "Handled")
+	if record := decodeRecord(t, &buffer); record[RequestIDKey] != "abc" {
+		t.Fatalf("Expected the attributes to be kept, got %v", record)
+	}
+}
+
+func TestMiddlewareAssignsRequestIDs(t *testing.T) {
+	var requestID string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, attr := range attrsFrom(r.Context()) {
+			if attr.Key == RequestIDKey {
+				requestID = attr.Value.String()
+			}
+		}
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if requestID == "" || w.Header().Get(RequestIDHeader) != requestID {
+		t.Fatalf("Expected a generated request ID, got %q and header %q", requestID, w.Header().Get(RequestIDHeader))
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(RequestIDHeader, "from-proxy")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if requestID != "from-proxy" {
+		t.Fatalf("Expected the client's request ID to be kept, got %q", requestID)
+	}
+}
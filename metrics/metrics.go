@@ -0,0 +1,400 @@
+// Package metrics exports counters, gauges and histograms in the Prometheus text format.
+// Metrics are created through a Registry, which writes every metric it created when it is scraped.
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the histogram buckets used for latencies, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultRegistry holds the metrics that aren't tied to a single GameServer, such as the latency of Slack API calls.
+var DefaultRegistry = NewRegistry()
+
+// metric is implemented by every metric type, writing its samples in the text format.
+type metric interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds a set of metrics with unique names.
+type Registry struct {
+	mutex   sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (registry *Registry) register(metric metric) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.names[metric.name()] {
+		panic(fmt.Sprintf("metrics: the %v metric is already registered", metric.name()))
+	}
+	registry.names[metric.name()] = true
+	registry.metrics = append(registry.metrics, metric)
+}
+
+// WriteTo writes every metric of the registry in the Prometheus text format, sorted by name.
+func (registry *Registry) WriteTo(w io.Writer) (int64, error) {
+	registry.mutex.Lock()
+	metrics := append([]metric(nil), registry.metrics...)
+	registry.mutex.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name() < metrics[j].name() })
+
+	counter := &countingWriter{Writer: w}
+	buffered := bufio.NewWriter(counter)
+	for _, metric := range metrics {
+		metric.write(buffered)
+	}
+	err := buffered.Flush()
+
+	return counter.written, err
+}
+
+// Handler serves the metrics of the registries in the Prometheus text format.
+func Handler(registries ...*Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, registry := range registries {
+			registry.WriteTo(w)
+		}
+	})
+}
+
+type countingWriter struct {
+	io.Writer
+	written int64
+}
+
+func (writer *countingWriter) Write(p []byte) (int, error) {
+	n, err := writer.Writer.Write(p)
+	writer.written += int64(n)
+	return n, err
+}
+
+// desc describes a metric and the names of its labels.
+type desc struct {
+	metricName string
+	help       string
+	metricType string
+	labels     []string
+}
+
+func (desc desc) name() string {
+	return desc.metricName
+}
+
+func (desc desc) writeHeader(w *bufio.Writer) {
+	help := strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(desc.help)
+	fmt.Fprintf(w, "# HELP %v %v\n# TYPE %v %v\n", desc.metricName, help, desc.metricName, desc.metricType)
+}
+
+// formatLabels formats the label pairs of a sample, with the extra pair appended when it isn't empty.
+func (desc desc) formatLabels(values []string, extra ...string) string {
+	var pairs []string
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	for i, label := range desc.labels {
+		pairs = append(pairs, fmt.Sprintf(`%v="%v"`, label, escaper.Replace(values[i])))
+	}
+	if len(extra) == 2 {
+		pairs = append(pairs, fmt.Sprintf(`%v="%v"`, extra[0], extra[1]))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (desc desc) key(values []string) string {
+	if len(values) != len(desc.labels) {
+		panic(fmt.Sprintf("metrics: the %v metric expects %v label values, got %v", desc.metricName, len(desc.labels), len(values)))
+	}
+
+	return strings.Join(values, "\xff")
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// CounterVec is a counter partitioned by its labels.
+type CounterVec struct {
+	desc
+	mutex  sync.Mutex
+	values map[string]float64
+	labels map[string][]string
+}
+
+// NewCounterVec creates and registers a counter partitioned by the labels.
+// Counters without labels are exported as zero until they are incremented, so their series exist from the first scrape.
+func (registry *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	counter := &CounterVec{
+		desc:   desc{metricName: name, help: help, metricType: "counter", labels: labels},
+		values: make(map[string]float64),
+		labels: make(map[string][]string),
+	}
+	if len(labels) == 0 {
+		counter.values[counter.key(nil)] = 0
+	}
+	registry.register(counter)
+
+	return counter
+}
+
+// Inc increments the counter with the label values by one.
+func (counter *CounterVec) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+// Add adds the value, which must not be negative, to the counter with the label values.
+func (counter *CounterVec) Add(value float64, labelValues ...string) {
+	key := counter.key(labelValues)
+
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	counter.values[key] += value
+	counter.labels[key] = labelValues
+}
+
+// Value returns the value of the counter with the label values.
+func (counter *CounterVec) Value(labelValues ...string) float64 {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	return counter.values[counter.key(labelValues)]
+}
+
+func (counter *CounterVec) write(w *bufio.Writer) {
+	counter.mutex.Lock()
+	defer counter.mutex.Unlock()
+
+	counter.writeHeader(w)
+	var keys []string
+	for key := range counter.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%v%v %v\n", counter.metricName, counter.formatLabels(counter.labels[key]), formatValue(counter.values[key]))
+	}
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	desc
+	mutex sync.Mutex
+	value float64
+}
+
+// NewGauge creates and registers a gauge.
+func (registry *Registry) NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{desc: desc{metricName: name, help: help, metricType: "gauge"}}
+	registry.register(gauge)
+
+	return gauge
+}
+
+// Add adds the value, which can be negative, to the gauge.
+func (gauge *Gauge) Add(value float64) {
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+
+	gauge.value += value
+}
+
+// Value returns the gauge's current value.
+func (gauge *Gauge) Value() float64 {
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+
+	return gauge.value
+}
+
+func (gauge *Gauge) write(w *bufio.Writer) {
+	gauge.writeHeader(w)
+	fmt.Fprintf(w, "%v %v\n", gauge.metricName, formatValue(gauge.Value()))
+}
+
+// GaugeFunc is a gauge whose value is computed whenever it is scraped.
+type GaugeFunc struct {
+	desc
+	value func() float64
+}
+
+// NewGaugeFunc creates and registers a gauge computed by value, which must be safe for concurrent use.
+func (registry *Registry) NewGaugeFunc(name, help string, value func() float64) *GaugeFunc {
+	gauge := &GaugeFunc{desc: desc{metricName: name, help: help, metricType: "gauge"}, value: value}
+	registry.register(gauge)
+
+	return gauge
+}
+
+func (gauge *GaugeFunc) write(w *bufio.Writer) {
+	gauge.writeHeader(w)
+	fmt.Fprintf(w, "%v %v\n", gauge.metricName, formatValue(gauge.value()))
+}
+
+// HistogramVec counts observations in buckets, partitioned by its labels.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	mutex   sync.Mutex
+	samples map[string]*histogramSample
+}
+
+type histogramSample struct {
+	labels []string
+	counts []uint64 // The number of observations in each bucket, the last one counting those above every bucket.
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec creates and registers a histogram with the buckets, which must be sorted, partitioned by the labels.
+func (registry *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	histogram := &HistogramVec{
+		desc:    desc{metricName: name, help: help, metricType: "histogram", labels: labels},
+		buckets: buckets,
+		samples: make(map[string]*histogramSample),
+	}
+	registry.register(histogram)
+
+	return histogram
+}
+
+// Observe adds the value to the histogram with the label values.
+func (histogram *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := histogram.key(labelValues)
+
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	sample, ok := histogram.samples[key]
+	if !ok {
+		sample = &histogramSample{labels: labelValues, counts: make([]uint64, len(histogram.buckets)+1)}
+		histogram.samples[key] = sample
+	}
+
+	sample.counts[sort.SearchFloat64s(histogram.buckets, value)]++
+	sample.count++
+	sample.sum += value
+}
+
+// Count returns the number of observations of the histogram with the label values.
+func (histogram *HistogramVec) Count(labelValues ...string) uint64 {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	if sample, ok := histogram.samples[histogram.key(labelValues)]; ok {
+		return sample.count
+	}
+	return 0
+}
+
+func (histogram *HistogramVec) write(w *bufio.Writer) {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	histogram.writeHeader(w)
+	var keys []string
+	for key := range histogram.samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	upperBounds := append(append([]float64(nil), histogram.buckets...), math.Inf(1))
+	for _, key := range keys {
+		sample := histogram.samples[key]
+
+		var cumulative uint64
+		for i, upperBound := range upperBounds {
+			cumulative += sample.counts[i]
+			labels := histogram.formatLabels(sample.labels, "le", formatValue(upperBound))
+			fmt.Fprintf(w, "%v_bucket%v %v\n", histogram.metricName, labels, cumulative)
+		}
+
+		labels := histogram.formatLabels(sample.labels)
+		fmt.Fprintf(w, "%v_sum%v %v\n", histogram.metricName, labels, formatValue(sample.sum))
+		fmt.Fprintf(w, "%v_count%v %v\n", histogram.metricName, labels, sample.count)
+	}
+}
+
+// InstrumentMux observes the latency of every request served by the mux, labelled by the pattern the request matched.
+func InstrumentMux(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+
+		recorder, ok := w.(*StatusRecorder)
+		if !ok {
+			recorder = NewStatusRecorder(w)
+		}
+		started := time.Now()
+
+		mux.ServeHTTP(recorder, r)
+
+		requestDuration.Observe(time.Since(started).Seconds(), pattern, r.Method, strconv.Itoa(recorder.Status))
+	})
+}
+
+var requestDuration = DefaultRegistry.NewHistogramVec(
+	"rps_http_request_duration_seconds",
+	"Latency of the HTTP requests handled by the server.",
+	DefaultBuckets,
+	"route", "method", "status",
+)
+
+// StatusRecorder records the status written by a handler, shared by the middlewares observing responses.
+// It can be hijacked for upgrading to websockets.
+type StatusRecorder struct {
+	http.ResponseWriter
+	Status int
+}
+
+// NewStatusRecorder creates a StatusRecorder for w, recording http.StatusOK until another status is written.
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (recorder *StatusRecorder) WriteHeader(status int) {
+	recorder.Status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *StatusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := recorder.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("The response writer does not support hijacking")
+	}
+
+	recorder.Status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWritesTextFormat(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounterVec("test_moves_total", "Moves played.", "move")
+	gauge := registry.NewGauge("test_queue_depth", "Queued jobs.")
+	registry.NewGaugeFunc("test_active", "Active sessions.", func() float64 { return 3 })
+	histogram := registry.NewHistogramVec("test_duration_seconds", "Durations.", []float64{0.1, 1}, "method")
+
+	counter.Inc("rock")
+	counter.Add(2, `pa"per`)
+	gauge.Add(2)
+	gauge.Add(-1)
+	histogram.Observe(0.05, "GET")
+	histogram.Observe(0.5, "GET")
+	histogram.Observe(5, "GET")
+
+	var buffer bytes.Buffer
+	registry.WriteTo(&buffer)
+
+	expected := `# HELP test_active Active sessions.
+# TYPE test_active gauge
+test_active 3
+# HELP test_duration_seconds Durations.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{method="GET",le="0.1"} 1
+test_duration_seconds_bucket{method="GET",le="1"} 2
+test_duration_seconds_bucket{method="GET",le="+Inf"} 3
+test_duration_seconds_sum{method="GET"} 5.55
+test_duration_seconds_count{method="GET"} 3
+# HELP test_moves_total Moves played.
+# TYPE test_moves_total counter
+test_moves_total{move="pa\"per"} 2
+test_moves_total{move="rock"} 1
+# HELP test_queue_depth Queued jobs.
+# TYPE test_queue_depth gauge
+test_queue_depth 1
+`
+	if buffer.String() != expected {
+		t.Fatalf("Unexpected output:\n%v", buffer.String())
+	}
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewGauge("test_gauge", "A gauge.")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected registering a duplicate name to panic")
+		}
+	}()
+	registry.NewGauge("test_gauge", "Another gauge.")
+}
+
+func TestInstrumentMuxLabelsRequestsByPattern(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sessions/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Not found", http.StatusNotFound)
+	})
+
+	before := requestDuration.Count("/sessions/", "GET", "404")
+	InstrumentMux(mux).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/sessions/abc", nil))
+
+	if requestDuration.Count("/sessions/", "GET", "404") != before+1 {
+		t.Fatal("Expected the request to be observed under its pattern")
+	}
+
+	w := httptest.NewRecorder()
+	Handler(DefaultRegistry).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(w.Body.String(), `rps_http_request_duration_seconds_count{route="/sessions/",method="GET",status="404"}`) {
+		t.Fatalf("Expected the request to be exported, got:\n%v", w.Body.String())
+	}
+}
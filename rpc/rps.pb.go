@@ -0,0 +1,1002 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: rps.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Outcome int32
+
+const (
+	Outcome_OUTCOME_UNSPECIFIED Outcome = 0
+	Outcome_OUTCOME_PLAYER_ONE  Outcome = 1
+	Outcome_OUTCOME_PLAYER_TWO  Outcome = 2
+	Outcome_OUTCOME_DRAW        Outcome = 3
+)
+
+// Enum value maps for Outcome.
+var (
+	Outcome_name = map[int32]string{
+		0: "OUTCOME_UNSPECIFIED",
+		1: "OUTCOME_PLAYER_ONE",
+		2: "OUTCOME_PLAYER_TWO",
+		3: "OUTCOME_DRAW",
+	}
+	Outcome_value = map[string]int32{
+		"OUTCOME_UNSPECIFIED": 0,
+		"OUTCOME_PLAYER_ONE":  1,
+		"OUTCOME_PLAYER_TWO":  2,
+		"OUTCOME_DRAW":        3,
+	}
+)
+
+func (x Outcome) Enum() *Outcome {
+	p := new(Outcome)
+	*p = x
+	return p
+}
+
+func (x Outcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Outcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_rps_proto_enumTypes[0].Descriptor()
+}
+
+func (Outcome) Type() protoreflect.EnumType {
+	return &file_rps_proto_enumTypes[0]
+}
+
+func (x Outcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Outcome.Descriptor instead.
+func (Outcome) EnumDescriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{0}
+}
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED EventType = 0
+	EventType_EVENT_TYPE_SNAPSHOT    EventType = 1
+	EventType_EVENT_TYPE_CREATED     EventType = 2
+	EventType_EVENT_TYPE_ACCEPTED    EventType = 3
+	EventType_EVENT_TYPE_LOCKED      EventType = 4
+	EventType_EVENT_TYPE_RESULT      EventType = 5
+	EventType_EVENT_TYPE_EXPIRED     EventType = 6
+	EventType_EVENT_TYPE_CANCELLED   EventType = 7
+	EventType_EVENT_TYPE_REPLAYED    EventType = 8
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_SNAPSHOT",
+		2: "EVENT_TYPE_CREATED",
+		3: "EVENT_TYPE_ACCEPTED",
+		4: "EVENT_TYPE_LOCKED",
+		5: "EVENT_TYPE_RESULT",
+		6: "EVENT_TYPE_EXPIRED",
+		7: "EVENT_TYPE_CANCELLED",
+		8: "EVENT_TYPE_REPLAYED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED": 0,
+		"EVENT_TYPE_SNAPSHOT":    1,
+		"EVENT_TYPE_CREATED":     2,
+		"EVENT_TYPE_ACCEPTED":    3,
+		"EVENT_TYPE_LOCKED":      4,
+		"EVENT_TYPE_RESULT":      5,
+		"EVENT_TYPE_EXPIRED":     6,
+		"EVENT_TYPE_CANCELLED":   7,
+		"EVENT_TYPE_REPLAYED":    8,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_rps_proto_enumTypes[1].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_rps_proto_enumTypes[1]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{1}
+}
+
+type Move struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Defeats       []string               `protobuf:"bytes,2,rep,name=defeats,proto3" json:"defeats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	mi := &file_rps_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Move) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Move) GetDefeats() []string {
+	if x != nil {
+		return x.Defeats
+	}
+	return nil
+}
+
+type Mode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Moves are listed in the mode's preferred display order.
+	Moves         []*Move `protobuf:"bytes,2,rep,name=moves,proto3" json:"moves,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Mode) Reset() {
+	*x = Mode{}
+	mi := &file_rps_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Mode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mode) ProtoMessage() {}
+
+func (x *Mode) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mode.ProtoReflect.Descriptor instead.
+func (*Mode) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Mode) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Mode) GetMoves() []*Move {
+	if x != nil {
+		return x.Moves
+	}
+	return nil
+}
+
+type ListModesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModesRequest) Reset() {
+	*x = ListModesRequest{}
+	mi := &file_rps_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModesRequest) ProtoMessage() {}
+
+func (x *ListModesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModesRequest.ProtoReflect.Descriptor instead.
+func (*ListModesRequest) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{2}
+}
+
+type ListModesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Modes         []*Mode                `protobuf:"bytes,1,rep,name=modes,proto3" json:"modes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModesResponse) Reset() {
+	*x = ListModesResponse{}
+	mi := &file_rps_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModesResponse) ProtoMessage() {}
+
+func (x *ListModesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModesResponse.ProtoReflect.Descriptor instead.
+func (*ListModesResponse) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListModesResponse) GetModes() []*Mode {
+	if x != nil {
+		return x.Modes
+	}
+	return nil
+}
+
+type PlayRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The registered game mode to play, the server's game is used when empty.
+	Mode          string `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	PlayerOneMove string `protobuf:"bytes,2,opt,name=player_one_move,json=playerOneMove,proto3" json:"player_one_move,omitempty"`
+	PlayerTwoMove string `protobuf:"bytes,3,opt,name=player_two_move,json=playerTwoMove,proto3" json:"player_two_move,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayRequest) Reset() {
+	*x = PlayRequest{}
+	mi := &file_rps_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayRequest) ProtoMessage() {}
+
+func (x *PlayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayRequest.ProtoReflect.Descriptor instead.
+func (*PlayRequest) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PlayRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *PlayRequest) GetPlayerOneMove() string {
+	if x != nil {
+		return x.PlayerOneMove
+	}
+	return ""
+}
+
+func (x *PlayRequest) GetPlayerTwoMove() string {
+	if x != nil {
+		return x.PlayerTwoMove
+	}
+	return ""
+}
+
+type PlayResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Outcome       Outcome                `protobuf:"varint,1,opt,name=outcome,proto3,enum=rps.v1.Outcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayResponse) Reset() {
+	*x = PlayResponse{}
+	mi := &file_rps_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayResponse) ProtoMessage() {}
+
+func (x *PlayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayResponse.ProtoReflect.Descriptor instead.
+func (*PlayResponse) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlayResponse) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+type CreateSessionRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Challenger string                 `protobuf:"bytes,1,opt,name=challenger,proto3" json:"challenger,omitempty"`
+	Target     string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// One of the registered game modes, the server's default game is used when empty.
+	Mode          string `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_rps_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateSessionRequest) GetChallenger() string {
+	if x != nil {
+		return x.Challenger
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type GetSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	mi := &file_rps_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SubmitMoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Player        string                 `protobuf:"bytes,2,opt,name=player,proto3" json:"player,omitempty"`
+	Move          string                 `protobuf:"bytes,3,opt,name=move,proto3" json:"move,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitMoveRequest) Reset() {
+	*x = SubmitMoveRequest{}
+	mi := &file_rps_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitMoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitMoveRequest) ProtoMessage() {}
+
+func (x *SubmitMoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitMoveRequest.ProtoReflect.Descriptor instead.
+func (*SubmitMoveRequest) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubmitMoveRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SubmitMoveRequest) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+func (x *SubmitMoveRequest) GetMove() string {
+	if x != nil {
+		return x.Move
+	}
+	return ""
+}
+
+type WatchSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSessionRequest) Reset() {
+	*x = WatchSessionRequest{}
+	mi := &file_rps_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSessionRequest) ProtoMessage() {}
+
+func (x *WatchSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSessionRequest.ProtoReflect.Descriptor instead.
+func (*WatchSessionRequest) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchSessionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Session struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Challenger       string                 `protobuf:"bytes,3,opt,name=challenger,proto3" json:"challenger,omitempty"`
+	Target           string                 `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	ChallengerLocked bool                   `protobuf:"varint,5,opt,name=challenger_locked,json=challengerLocked,proto3" json:"challenger_locked,omitempty"`
+	TargetLocked     bool                   `protobuf:"varint,6,opt,name=target_locked,json=targetLocked,proto3" json:"target_locked,omitempty"`
+	// Moves are only revealed once the session has an outcome.
+	ChallengerMove string  `protobuf:"bytes,7,opt,name=challenger_move,json=challengerMove,proto3" json:"challenger_move,omitempty"`
+	TargetMove     string  `protobuf:"bytes,8,opt,name=target_move,json=targetMove,proto3" json:"target_move,omitempty"`
+	Outcome        Outcome `protobuf:"varint,9,opt,name=outcome,proto3,enum=rps.v1.Outcome" json:"outcome,omitempty"`
+	// Empty when the session is pending or ended in a draw.
+	Winner string `protobuf:"bytes,10,opt,name=winner,proto3" json:"winner,omitempty"`
+	// Empty for sessions played with the server's default game.
+	Mode          string `protobuf:"bytes,11,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_rps_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Session) GetChallenger() string {
+	if x != nil {
+		return x.Challenger
+	}
+	return ""
+}
+
+func (x *Session) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Session) GetChallengerLocked() bool {
+	if x != nil {
+		return x.ChallengerLocked
+	}
+	return false
+}
+
+func (x *Session) GetTargetLocked() bool {
+	if x != nil {
+		return x.TargetLocked
+	}
+	return false
+}
+
+func (x *Session) GetChallengerMove() string {
+	if x != nil {
+		return x.ChallengerMove
+	}
+	return ""
+}
+
+func (x *Session) GetTargetMove() string {
+	if x != nil {
+		return x.TargetMove
+	}
+	return ""
+}
+
+func (x *Session) GetOutcome() Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return Outcome_OUTCOME_UNSPECIFIED
+}
+
+func (x *Session) GetWinner() string {
+	if x != nil {
+		return x.Winner
+	}
+	return ""
+}
+
+func (x *Session) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type SessionEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=rps.v1.EventType" json:"type,omitempty"`
+	Player        string                 `protobuf:"bytes,2,opt,name=player,proto3" json:"player,omitempty"`
+	Session       *Session               `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionEvent) Reset() {
+	*x = SessionEvent{}
+	mi := &file_rps_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEvent) ProtoMessage() {}
+
+func (x *SessionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rps_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return file_rps_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SessionEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *SessionEvent) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *SessionEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_rps_proto protoreflect.FileDescriptor
+
+const file_rps_proto_rawDesc = "" +
+	"\n" +
+	"\trps.proto\x12\x06rps.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"4\n" +
+	"\x04Move\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\adefeats\x18\x02 \x03(\tR\adefeats\">\n" +
+	"\x04Mode\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\"\n" +
+	"\x05moves\x18\x02 \x03(\v2\f.rps.v1.MoveR\x05moves\"\x12\n" +
+	"\x10ListModesRequest\"7\n" +
+	"\x11ListModesResponse\x12\"\n" +
+	"\x05modes\x18\x01 \x03(\v2\f.rps.v1.ModeR\x05modes\"q\n" +
+	"\vPlayRequest\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12&\n" +
+	"\x0fplayer_one_move\x18\x02 \x01(\tR\rplayerOneMove\x12&\n" +
+	"\x0fplayer_two_move\x18\x03 \x01(\tR\rplayerTwoMove\"9\n" +
+	"\fPlayResponse\x12)\n" +
+	"\aoutcome\x18\x01 \x01(\x0e2\x0f.rps.v1.OutcomeR\aoutcome\"b\n" +
+	"\x14CreateSessionRequest\x12\x1e\n" +
+	"\n" +
+	"challenger\x18\x01 \x01(\tR\n" +
+	"challenger\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\"#\n" +
+	"\x11GetSessionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"^\n" +
+	"\x11SubmitMoveRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06player\x18\x02 \x01(\tR\x06player\x12\x12\n" +
+	"\x04move\x18\x03 \x01(\tR\x04move\"%\n" +
+	"\x13WatchSessionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xff\x02\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1e\n" +
+	"\n" +
+	"challenger\x18\x03 \x01(\tR\n" +
+	"challenger\x12\x16\n" +
+	"\x06target\x18\x04 \x01(\tR\x06target\x12+\n" +
+	"\x11challenger_locked\x18\x05 \x01(\bR\x10challengerLocked\x12#\n" +
+	"\rtarget_locked\x18\x06 \x01(\bR\ftargetLocked\x12'\n" +
+	"\x0fchallenger_move\x18\a \x01(\tR\x0echallengerMove\x12\x1f\n" +
+	"\vtarget_move\x18\b \x01(\tR\n" +
+	"targetMove\x12)\n" +
+	"\aoutcome\x18\t \x01(\x0e2\x0f.rps.v1.OutcomeR\aoutcome\x12\x16\n" +
+	"\x06winner\x18\n" +
+	" \x01(\tR\x06winner\x12\x12\n" +
+	"\x04mode\x18\v \x01(\tR\x04mode\"\xb2\x01\n" +
+	"\fSessionEvent\x12%\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x11.rps.v1.EventTypeR\x04type\x12\x16\n" +
+	"\x06player\x18\x02 \x01(\tR\x06player\x12)\n" +
+	"\asession\x18\x03 \x01(\v2\x0f.rps.v1.SessionR\asession\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp*d\n" +
+	"\aOutcome\x12\x17\n" +
+	"\x13OUTCOME_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12OUTCOME_PLAYER_ONE\x10\x01\x12\x16\n" +
+	"\x12OUTCOME_PLAYER_TWO\x10\x02\x12\x10\n" +
+	"\fOUTCOME_DRAW\x10\x03*\xea\x01\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13EVENT_TYPE_SNAPSHOT\x10\x01\x12\x16\n" +
+	"\x12EVENT_TYPE_CREATED\x10\x02\x12\x17\n" +
+	"\x13EVENT_TYPE_ACCEPTED\x10\x03\x12\x15\n" +
+	"\x11EVENT_TYPE_LOCKED\x10\x04\x12\x15\n" +
+	"\x11EVENT_TYPE_RESULT\x10\x05\x12\x16\n" +
+	"\x12EVENT_TYPE_EXPIRED\x10\x06\x12\x18\n" +
+	"\x14EVENT_TYPE_CANCELLED\x10\a\x12\x17\n" +
+	"\x13EVENT_TYPE_REPLAYED\x10\b2\xfb\x02\n" +
+	"\vGameService\x12@\n" +
+	"\tListModes\x12\x18.rps.v1.ListModesRequest\x1a\x19.rps.v1.ListModesResponse\x121\n" +
+	"\x04Play\x12\x13.rps.v1.PlayRequest\x1a\x14.rps.v1.PlayResponse\x12>\n" +
+	"\rCreateSession\x12\x1c.rps.v1.CreateSessionRequest\x1a\x0f.rps.v1.Session\x128\n" +
+	"\n" +
+	"GetSession\x12\x19.rps.v1.GetSessionRequest\x1a\x0f.rps.v1.Session\x128\n" +
+	"\n" +
+	"SubmitMove\x12\x19.rps.v1.SubmitMoveRequest\x1a\x0f.rps.v1.Session\x12C\n" +
+	"\fWatchSession\x12\x1b.rps.v1.WatchSessionRequest\x1a\x14.rps.v1.SessionEvent0\x01B\x1fZ\x1dgithub.com/hamologist/rps/rpcb\x06proto3"
+
+var (
+	file_rps_proto_rawDescOnce sync.Once
+	file_rps_proto_rawDescData []byte
+)
+
+func file_rps_proto_rawDescGZIP() []byte {
+	file_rps_proto_rawDescOnce.Do(func() {
+		file_rps_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rps_proto_rawDesc), len(file_rps_proto_rawDesc)))
+	})
+	return file_rps_proto_rawDescData
+}
+
+var file_rps_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_rps_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_rps_proto_goTypes = []any{
+	(Outcome)(0),                  // 0: rps.v1.Outcome
+	(EventType)(0),                // 1: rps.v1.EventType
+	(*Move)(nil),                  // 2: rps.v1.Move
+	(*Mode)(nil),                  // 3: rps.v1.Mode
+	(*ListModesRequest)(nil),      // 4: rps.v1.ListModesRequest
+	(*ListModesResponse)(nil),     // 5: rps.v1.ListModesResponse
+	(*PlayRequest)(nil),           // 6: rps.v1.PlayRequest
+	(*PlayResponse)(nil),          // 7: rps.v1.PlayResponse
+	(*CreateSessionRequest)(nil),  // 8: rps.v1.CreateSessionRequest
+	(*GetSessionRequest)(nil),     // 9: rps.v1.GetSessionRequest
+	(*SubmitMoveRequest)(nil),     // 10: rps.v1.SubmitMoveRequest
+	(*WatchSessionRequest)(nil),   // 11: rps.v1.WatchSessionRequest
+	(*Session)(nil),               // 12: rps.v1.Session
+	(*SessionEvent)(nil),          // 13: rps.v1.SessionEvent
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
+}
+var file_rps_proto_depIdxs = []int32{
+	2,  // 0: rps.v1.Mode.moves:type_name -> rps.v1.Move
+	3,  // 1: rps.v1.ListModesResponse.modes:type_name -> rps.v1.Mode
+	0,  // 2: rps.v1.PlayResponse.outcome:type_name -> rps.v1.Outcome
+	14, // 3: rps.v1.Session.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: rps.v1.Session.outcome:type_name -> rps.v1.Outcome
+	1,  // 5: rps.v1.SessionEvent.type:type_name -> rps.v1.EventType
+	12, // 6: rps.v1.SessionEvent.session:type_name -> rps.v1.Session
+	14, // 7: rps.v1.SessionEvent.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 8: rps.v1.GameService.ListModes:input_type -> rps.v1.ListModesRequest
+	6,  // 9: rps.v1.GameService.Play:input_type -> rps.v1.PlayRequest
+	8,  // 10: rps.v1.GameService.CreateSession:input_type -> rps.v1.CreateSessionRequest
+	9,  // 11: rps.v1.GameService.GetSession:input_type -> rps.v1.GetSessionRequest
+	10, // 12: rps.v1.GameService.SubmitMove:input_type -> rps.v1.SubmitMoveRequest
+	11, // 13: rps.v1.GameService.WatchSession:input_type -> rps.v1.WatchSessionRequest
+	5,  // 14: rps.v1.GameService.ListModes:output_type -> rps.v1.ListModesResponse
+	7,  // 15: rps.v1.GameService.Play:output_type -> rps.v1.PlayResponse
+	12, // 16: rps.v1.GameService.CreateSession:output_type -> rps.v1.Session
+	12, // 17: rps.v1.GameService.GetSession:output_type -> rps.v1.Session
+	12, // 18: rps.v1.GameService.SubmitMove:output_type -> rps.v1.Session
+	13, // 19: rps.v1.GameService.WatchSession:output_type -> rps.v1.SessionEvent
+	14, // [14:20] is the sub-list for method output_type
+	8,  // [8:14] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_rps_proto_init() }
+func file_rps_proto_init() {
+	if File_rps_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rps_proto_rawDesc), len(file_rps_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rps_proto_goTypes,
+		DependencyIndexes: file_rps_proto_depIdxs,
+		EnumInfos:         file_rps_proto_enumTypes,
+		MessageInfos:      file_rps_proto_msgTypes,
+	}.Build()
+	File_rps_proto = out.File
+	file_rps_proto_goTypes = nil
+	file_rps_proto_depIdxs = nil
+}
@@ -0,0 +1,246 @@
+// Package rpc provides a gRPC service for resolving games of RPS and managing the sessions of a server.GameServer.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative rps.proto
+
+import (
+	"context"
+	"crypto/subtle"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+var outcomes = map[string]Outcome{
+	game.GameStatePlayerOneWins: Outcome_OUTCOME_PLAYER_ONE,
+	game.GameStatePlayerTwoWins: Outcome_OUTCOME_PLAYER_TWO,
+	game.GameStateDraw:          Outcome_OUTCOME_DRAW,
+}
+
+var eventTypes = map[string]EventType{
+	server.EventCreated:   EventType_EVENT_TYPE_CREATED,
+	server.EventAccepted:  EventType_EVENT_TYPE_ACCEPTED,
+	server.EventLocked:    EventType_EVENT_TYPE_LOCKED,
+	server.EventResult:    EventType_EVENT_TYPE_RESULT,
+	server.EventExpired:   EventType_EVENT_TYPE_EXPIRED,
+	server.EventCancelled: EventType_EVENT_TYPE_CANCELLED,
+	server.EventReplayed:  EventType_EVENT_TYPE_REPLAYED,
+}
+
+// GameService implements GameServiceServer on top of a server.GameServer.
+type GameService struct {
+	UnimplementedGameServiceServer
+	*server.GameServer
+}
+
+// NewGRPCServer creates a grpc.Server serving a GameService for the provided GameServer.
+// Every call must provide one of the tokens as a Bearer token using the "authorization" metadata key.
+func NewGRPCServer(gameServer *server.GameServer, tokens []string, opts ...grpc.ServerOption) *grpc.Server {
+	auth := tokenAuth(tokens)
+	opts = append(opts,
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := auth(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := auth(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+
+	grpcServer := grpc.NewServer(opts...)
+	RegisterGameServiceServer(grpcServer, &GameService{GameServer: gameServer})
+
+	return grpcServer
+}
+
+// ListModes returns every game mode registered in modes.RegisteredGames.
+func (service *GameService) ListModes(ctx context.Context, request *ListModesRequest) (*ListModesResponse, error) {
+	var names []string
+	response := &ListModesResponse{}
+
+	for name := range modes.RegisteredGames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		registeredGame := modes.RegisteredGames[name]
+		mode := &Mode{Name: name}
+
+		for _, move := range registeredGame.PreferredOrder {
+			mode.Moves = append(mode.Moves, &Move{Name: move, Defeats: registeredGame.Moves[move].Defeats})
+		}
+		response.Modes = append(response.Modes, mode)
+	}
+
+	return response, nil
+}
+
+// Play resolves the outcome of two moves using the requested mode, or the GameServer's Game if no mode is provided.
+func (service *GameService) Play(ctx context.Context, request *PlayRequest) (*PlayResponse, error) {
+	rpsGame := service.Game
+
+	if request.Mode != "" {
+		registeredGame, ok := modes.RegisteredGames[request.Mode]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Unknown game mode: %v", request.Mode)
+		}
+		rpsGame = registeredGame
+	}
+
+	result, err := rpsGame.Play(request.PlayerOneMove, request.PlayerTwoMove)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &PlayResponse{Outcome: outcomes[result]}, nil
+}
+
+// CreateSession creates a session using the GameServer's GameSessionsManager.
+func (service *GameService) CreateSession(ctx context.Context, request *CreateSessionRequest) (*Session, error) {
+	if request.Challenger == "" || request.Target == "" {
+		return nil, status.Error(codes.InvalidArgument, "Both a challenger and a target are required")
+	}
+
+	if request.Challenger == request.Target {
+		return nil, status.Error(codes.InvalidArgument, "A player can't challenge themselves")
+	}
+
+	id, err := service.GameSessionsManager.CreateModeSession(request.Challenger, request.Target, request.Mode, nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	v, _ := service.GameSessionsManager.Session(id)
+
+	return newSession(v), nil
+}
+
+// GetSession returns the session with the requested id.
+func (service *GameService) GetSession(ctx context.Context, request *GetSessionRequest) (*Session, error) {
+	v, ok := service.GameSessionsManager.Session(request.Id)
+	if !ok {
+		return nil, status.Error(codes.NotFound, server.ErrSessionNotFound.Error())
+	}
+
+	return newSession(v), nil
+}
+
+// SubmitMove locks in a player's move using the GameServer.
+func (service *GameService) SubmitMove(ctx context.Context, request *SubmitMoveRequest) (*Session, error) {
+	v, err := service.GameServer.SubmitMove(request.SessionId, request.Player, request.Move)
+
+	switch err {
+	case nil:
+		return newSession(v), nil
+	case server.ErrSessionNotFound:
+		return nil, status.Error(codes.NotFound, err.Error())
+	case server.ErrNotSessionPlayer:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case server.ErrInvalidMove:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case server.ErrMoveLocked, server.ErrSessionComplete:
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+}
+
+// WatchSession streams the events of a session until it has a result, has expired or the client goes away.
+func (service *GameService) WatchSession(request *WatchSessionRequest, stream GameService_WatchSessionServer) error {
+	events, unsubscribe := service.GameSessionsManager.Events.Subscribe(request.Id)
+	defer unsubscribe()
+
+	v, ok := service.GameSessionsManager.Session(request.Id)
+	if !ok {
+		return status.Error(codes.NotFound, server.ErrSessionNotFound.Error())
+	}
+
+	err := stream.Send(&SessionEvent{
+		Type:      EventType_EVENT_TYPE_SNAPSHOT,
+		Session:   newSession(v),
+		Timestamp: timestamppb.Now(),
+	})
+	if err != nil || v.Result != "" {
+		return err
+	}
+
+	for {
+		select {
+		case event := <-events:
+			err := stream.Send(&SessionEvent{
+				Type:      eventTypes[event.Type],
+				Player:    event.Player,
+				Session:   newSession(event.Session),
+				Timestamp: timestamppb.New(event.Timestamp),
+			})
+			if err != nil {
+				return err
+			}
+
+			if event.Type == server.EventResult || event.Type == server.EventExpired || event.Type == server.EventCancelled {
+				return nil
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// newSession converts a server.GameSession, hiding both moves until the session has a result.
+func newSession(v server.GameSession) *Session {
+	session := &Session{
+		Id:               v.ID,
+		CreatedAt:        timestamppb.New(v.Timestamp),
+		Challenger:       v.Challenger,
+		Target:           v.Target,
+		Mode:             v.Mode,
+		ChallengerLocked: v.ChallengerMove != "",
+		TargetLocked:     v.TargetMove != "",
+	}
+
+	if v.Result != "" {
+		session.ChallengerMove = v.ChallengerMove
+		session.TargetMove = v.TargetMove
+		session.Outcome = outcomes[v.Result]
+
+		if v.Result == game.GameStatePlayerOneWins {
+			session.Winner = v.Challenger
+		} else if v.Result == game.GameStatePlayerTwoWins {
+			session.Winner = v.Target
+		}
+	}
+
+	return session
+}
+
+func tokenAuth(tokens []string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		for _, header := range md.Get("authorization") {
+			token := strings.TrimPrefix(header, "Bearer ")
+
+			for _, v := range tokens {
+				if v != "" && subtle.ConstantTimeCompare([]byte(token), []byte(v)) == 1 {
+					return nil
+				}
+			}
+		}
+
+		return status.Error(codes.Unauthenticated, "A valid API token is required")
+	}
+}
@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+const apiToken = "secret"
+
+func TestListModes(t *testing.T) {
+	client, _ := createMockClient(t)
+	response, err := client.ListModes(authContext(t), &ListModesRequest{})
+
+	if err != nil {
+		t.Fatalf("ListModes should not have caused an error: %q", err)
+	}
+
+	if len(response.Modes) != len(modes.RegisteredGames) || response.Modes[0].Moves[0].Name != "rock" {
+		t.Fatalf("Unexpected modes: %v", response.Modes)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	client, _ := createMockClient(t)
+	response, err := client.Play(authContext(t), &PlayRequest{Mode: "standard", PlayerOneMove: "paper", PlayerTwoMove: "rock"})
+
+	if err != nil || response.Outcome != Outcome_OUTCOME_PLAYER_ONE {
+		t.Fatalf("Paper failed to beat rock: %v %q", response, err)
+	}
+
+	_, err = client.Play(authContext(t), &PlayRequest{PlayerOneMove: "lizard", PlayerTwoMove: "rock"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected an invalid argument error, got %q", err)
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	client, _ := createMockClient(t)
+	ctx := authContext(t)
+
+	session, err := client.CreateSession(ctx, &CreateSessionRequest{Challenger: "alice", Target: "bob"})
+	if err != nil {
+		t.Fatalf("CreateSession should not have caused an error: %q", err)
+	}
+
+	stream, err := client.WatchSession(ctx, &WatchSessionRequest{Id: session.Id})
+	if err != nil {
+		t.Fatalf("WatchSession should not have caused an error: %q", err)
+	}
+
+	if event, err := stream.Recv(); err != nil || event.Type != EventType_EVENT_TYPE_SNAPSHOT {
+		t.Fatalf("Expected a snapshot event, got %v %q", event, err)
+	}
+
+	session, _ = client.SubmitMove(ctx, &SubmitMoveRequest{SessionId: session.Id, Player: "alice", Move: "rock"})
+	if !session.ChallengerLocked || session.ChallengerMove != "" {
+		t.Fatalf("Challenger's move should be locked in but hidden: %v", session)
+	}
+	client.SubmitMove(ctx, &SubmitMoveRequest{SessionId: session.Id, Player: "bob", Move: "paper"})
+
+	var event *SessionEvent
+	for _, expected := range []EventType{EventType_EVENT_TYPE_LOCKED, EventType_EVENT_TYPE_ACCEPTED, EventType_EVENT_TYPE_LOCKED, EventType_EVENT_TYPE_RESULT} {
+		if event, err = stream.Recv(); err != nil || event.Type != expected {
+			t.Fatalf("Expected a %v event, got %v %q", expected, event, err)
+		}
+	}
+
+	if event.Session.Outcome != Outcome_OUTCOME_PLAYER_TWO || event.Session.Winner != "bob" {
+		t.Fatalf("Expected bob to have won: %v", event.Session)
+	}
+
+	session, _ = client.GetSession(ctx, &GetSessionRequest{Id: session.Id})
+	if session.ChallengerMove != "rock" || session.TargetMove != "paper" {
+		t.Fatalf("Moves should be revealed once the session has an outcome: %v", session)
+	}
+
+	_, err = client.SubmitMove(ctx, &SubmitMoveRequest{SessionId: session.Id, Player: "bob", Move: "rock"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected a failed precondition error, got %q", err)
+	}
+}
+
+func TestWatchSessionReportsCancellations(t *testing.T) {
+	client, gameServer := createMockClient(t)
+	ctx := authContext(t)
+	id := gameServer.GameSessionsManager.CreateSession("alice", "bob", nil)
+
+	stream, err := client.WatchSession(ctx, &WatchSessionRequest{Id: id})
+	if err != nil {
+		t.Fatalf("WatchSession should not have caused an error: %q", err)
+	}
+	stream.Recv()
+
+	gameServer.GameSessionsManager.CancelSession(id, "alice")
+	if event, err := stream.Recv(); err != nil || event.Type != EventType_EVENT_TYPE_CANCELLED {
+		t.Fatalf("Expected a cancelled event, got %v %q", event, err)
+	}
+}
+
+func TestSubmitMoveErrors(t *testing.T) {
+	client, gameServer := createMockClient(t)
+	ctx := authContext(t)
+	id := gameServer.GameSessionsManager.CreateSession("alice", "bob", nil)
+
+	for request, code := range map[*SubmitMoveRequest]codes.Code{
+		{SessionId: "missing", Player: "alice", Move: "rock"}: codes.NotFound,
+		{SessionId: id, Player: "eve", Move: "rock"}:          codes.PermissionDenied,
+		{SessionId: id, Player: "alice", Move: "lizard"}:      codes.InvalidArgument,
+	} {
+		if _, err := client.SubmitMove(ctx, request); status.Code(err) != code {
+			t.Fatalf("Expected %v for %v, got %q", code, request, err)
+		}
+	}
+}
+
+func TestRequiresToken(t *testing.T) {
+	client, _ := createMockClient(t)
+	_, err := client.ListModes(context.Background(), &ListModesRequest{})
+
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Expected an unauthenticated error, got %q", err)
+	}
+}
+
+func createMockClient(t *testing.T) (GameServiceClient, *server.GameServer) {
+	gameServer := server.NewGameServer(modes.StandardGame)
+	grpcServer := NewGRPCServer(gameServer, []string{apiToken})
+	listener := bufconn.Listen(1024 * 1024)
+
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %q", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewGameServiceClient(conn), gameServer
+}
+
+func authContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+apiToken)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AdminSessionsRoute defines the route used for listing every session.
+// Individual sessions live under AdminSessionsRoute/{id}, and are managed by posting to
+// AdminSessionsRoute/{id}/expire, AdminSessionsRoute/{id}/cancel or AdminSessionsRoute/{id}/replay.
+const AdminSessionsRoute = "/admin/sessions"
+
+// AdminActor is the actor of the events published when an admin expires a session or replays its result.
+const AdminActor = "admin"
+
+type adminController struct {
+	*apiController
+}
+
+// adminSessionResponse is the admin view of a session, which always reveals the moves along with the session's Data.
+type adminSessionResponse struct {
+	sessionResponse
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// RegisterAdminRoutes registers the admin API for inspecting and managing sessions on the GameServer's ServeMux.
+// Every request must provide one of the tokens as a Bearer token.
+func (gameServer *GameServer) RegisterAdminRoutes(tokens []string) {
+	controller := &adminController{&apiController{GameServer: gameServer, tokens: tokens}}
+	serveMux := gameServer.ServeMux
+
+	serveMux.HandleFunc(AdminSessionsRoute, controller.authenticate(controller.HandleSessions))
+	serveMux.HandleFunc(AdminSessionsRoute+"/", controller.authenticate(controller.HandleSession))
+}
+
+// HandleSessions lists the sessions, oldest first.
+// Sessions can be filtered by status (pending or complete), player, mode and age (using older_than, e.g. 10m).
+func (controller *adminController) HandleSessions(w http.ResponseWriter, r *http.Request) {
+	var olderThan time.Duration
+
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	status, player, mode := query.Get("status"), query.Get("player"), query.Get("mode")
+
+	if status != "" && status != sessionStatusPending && status != sessionStatusComplete {
+		writeError(w, http.StatusBadRequest, "The status must be either pending or complete")
+		return
+	}
+
+	if query.Get("older_than") != "" {
+		var err error
+		if olderThan, err = time.ParseDuration(query.Get("older_than")); err != nil {
+			writeError(w, http.StatusBadRequest, "The older_than filter must be a duration, such as 10m")
+			return
+		}
+	}
+
+	responses := []adminSessionResponse{}
+	for _, v := range controller.GameSessionsManager.Sessions() {
+		response := newAdminSessionResponse(v)
+
+		if (status != "" && response.Status != status) ||
+			(player != "" && v.Challenger != player && v.Target != player) ||
+			(mode != "" && v.Mode != mode) ||
+			time.Since(v.Timestamp) < olderThan {
+			continue
+		}
+
+		responses = append(responses, response)
+	}
+
+	writeJSON(w, http.StatusOK, responses)
+}
+
+func (controller *adminController) HandleSession(w http.ResponseWriter, r *http.Request) {
+	var (
+		v   GameSession
+		ok  bool
+		err error
+	)
+
+	tokens := strings.Split(strings.TrimPrefix(r.URL.Path, AdminSessionsRoute+"/"), "/")
+	id := tokens[0]
+	sessionManager := controller.GameSessionsManager
+
+	switch {
+	case len(tokens) == 1 && r.Method == "GET":
+		if v, ok = sessionManager.Session(id); !ok {
+			err = ErrSessionNotFound
+		}
+	case len(tokens) == 2 && tokens[1] == "expire" && r.Method == "POST":
+		v, err = sessionManager.ExpireSession(id, AdminActor)
+	case len(tokens) == 2 && tokens[1] == "cancel" && r.Method == "POST":
+		v, err = sessionManager.CancelSession(id, "")
+	case len(tokens) == 2 && tokens[1] == "replay" && r.Method == "POST":
+		v, err = sessionManager.ReplayResult(id, AdminActor)
+	case len(tokens) <= 2:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	switch err {
+	case nil:
+		writeJSON(w, http.StatusOK, newAdminSessionResponse(v))
+	case ErrSessionNotFound:
+		writeError(w, http.StatusNotFound, err.Error())
+	case ErrSessionComplete, ErrSessionPending:
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func newAdminSessionResponse(v GameSession) adminSessionResponse {
+	response := newSessionResponse(v)
+	response.ChallengerMove = v.ChallengerMove
+	response.TargetMove = v.TargetMove
+
+	return adminSessionResponse{sessionResponse: response, Data: v.Data}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hamologist/rps/game/modes"
+)
+
+func createMockAdminServer() *GameServer {
+	gameServer := NewGameServer(modes.StandardGame)
+	gameServer.RegisterAdminRoutes([]string{apiToken})
+
+	return gameServer
+}
+
+func TestAdminRejectsMissingToken(t *testing.T) {
+	gameServer := createMockAdminServer()
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, httptest.NewRequest("GET", AdminSessionsRoute, nil))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status %v, got %v", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAdminListsSessionsWithFilters(t *testing.T) {
+	var sessions []adminSessionResponse
+	gameServer := createMockAdminServer()
+	sessionManager := gameServer.GameSessionsManager
+
+	played := sessionManager.CreateSession("alice", "bob", nil)
+	pending, _ := sessionManager.CreateModeSession("alice", "carol", "rpsls", nil)
+	gameServer.SubmitMove(played, "alice", "rock")
+	gameServer.SubmitMove(played, "bob", "paper")
+	gameServer.SubmitMove(pending, "carol", "spock")
+
+	w := doAPIRequest(gameServer, "GET", AdminSessionsRoute+"?player=alice&status=pending", "")
+	json.Unmarshal(w.Body.Bytes(), &sessions)
+	if len(sessions) != 1 || sessions[0].ID != pending || sessions[0].TargetMove != "spock" {
+		t.Fatalf("Expected the pending session with its locked in move, got %+v", sessions)
+	}
+
+	w = doAPIRequest(gameServer, "GET", AdminSessionsRoute+"?mode=rpsls&older_than=1h", "")
+	json.Unmarshal(w.Body.Bytes(), &sessions)
+	if len(sessions) != 0 {
+		t.Fatalf("Expected no session older than an hour, got %+v", sessions)
+	}
+
+	if w := doAPIRequest(gameServer, "GET", AdminSessionsRoute+"?status=stuck", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected an unknown status to be rejected, got %v", w.Code)
+	}
+}
+
+func TestAdminManagesSessions(t *testing.T) {
+	gameServer := createMockAdminServer()
+	sessionManager := gameServer.GameSessionsManager
+	events, unsubscribe := sessionManager.Events.SubscribeAll()
+	defer unsubscribe()
+
+	id := sessionManager.CreateSession("alice", "bob", nil)
+	receiveEvent(t, events)
+
+	if w := doAPIRequest(gameServer, "POST", AdminSessionsRoute+"/"+id+"/replay", ""); w.Code != http.StatusConflict {
+		t.Fatalf("Expected replaying a pending session to conflict, got %v", w.Code)
+	}
+
+	if w := doAPIRequest(gameServer, "POST", AdminSessionsRoute+"/"+id+"/cancel", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected the session to be cancelled, got %v: %v", w.Code, w.Body)
+	}
+	if event := receiveEvent(t, events); event.Type != EventCancelled || event.Player != "" {
+		t.Fatalf("Expected the cancellation to be published, got %+v", event)
+	}
+	if w := doAPIRequest(gameServer, "GET", AdminSessionsRoute+"/"+id, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("Expected the cancelled session to be removed, got %v", w.Code)
+	}
+
+	id = sessionManager.CreateSession("alice", "bob", nil)
+	gameServer.SubmitMove(id, "alice", "rock")
+	gameServer.SubmitMove(id, "bob", "paper")
+	for event := receiveEvent(t, events); event.Type != EventResult; event = receiveEvent(t, events) {
+	}
+
+	if w := doAPIRequest(gameServer, "POST", AdminSessionsRoute+"/"+id+"/replay", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected the result to be replayed, got %v: %v", w.Code, w.Body)
+	}
+	if event := receiveEvent(t, events); event.Type != EventReplayed || event.Player != AdminActor || event.Session.Result == "" {
+		t.Fatalf("Expected the replay to be published, got %+v", event)
+	}
+
+	if w := doAPIRequest(gameServer, "POST", AdminSessionsRoute+"/"+id+"/expire", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected the session to be expired, got %v", w.Code)
+	}
+	if _, ok := sessionManager.Session(id); ok {
+		t.Fatal("Expected the expired session to be removed")
+	}
+
+	id = sessionManager.CreateSession("alice", "bob", nil)
+	receiveEvent(t, events)
+	doAPIRequest(gameServer, "POST", AdminSessionsRoute+"/"+id+"/expire", "")
+	if event := receiveEvent(t, events); event.Type != EventExpired || event.Player != AdminActor {
+		t.Fatalf("Expected the expiry to be attributed to the admin, got %+v", event)
+	}
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"crypto/subtle"
+	_ "embed" // Needed for embedding the OpenAPI spec.
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+)
+
+const (
+	// GamesRoute defines the route used for creating game sessions.
+	// Individual sessions live under GamesRoute/{id}, with moves submitted to GamesRoute/{id}/moves
+	// and a WebSocket of session events served from GamesRoute/{id}/events.
+	GamesRoute = "/games"
+	// ModesRoute defines the route used for listing the registered game modes.
+	ModesRoute = "/modes"
+	// OpenAPIRoute defines the route serving the OpenAPI spec for the REST API.
+	OpenAPIRoute = "/openapi.json"
+
+	sessionStatusPending  = "pending"
+	sessionStatusComplete = "complete"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+type apiController struct {
+	*GameServer
+	tokens []string
+}
+
+type createGameRequest struct {
+	Challenger string `json:"challenger"`
+	Target     string `json:"target"`
+	Mode       string `json:"mode"`
+}
+
+type submitMoveRequest struct {
+	Player string `json:"player"`
+	Move   string `json:"move"`
+}
+
+type sessionResponse struct {
+	ID               string    `json:"id"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+	Challenger       string    `json:"challenger"`
+	Target           string    `json:"target"`
+	Mode             string    `json:"mode,omitempty"`
+	ChallengerLocked bool      `json:"challenger_locked"`
+	TargetLocked     bool      `json:"target_locked"`
+	ChallengerMove   string    `json:"challenger_move,omitempty"`
+	TargetMove       string    `json:"target_move,omitempty"`
+	Result           string    `json:"result,omitempty"`
+	Winner           string    `json:"winner,omitempty"`
+}
+
+type modeResponse struct {
+	Name  string         `json:"name"`
+	Moves []moveResponse `json:"moves"`
+}
+
+type moveResponse struct {
+	Name    string   `json:"name"`
+	Defeats []string `json:"defeats"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// RegisterAPIRoutes registers the transport-neutral REST API on the GameServer's ServeMux.
+// Every request, other than those for the OpenAPI spec, must provide one of the tokens as a Bearer token.
+func (gameServer *GameServer) RegisterAPIRoutes(tokens []string) {
+	controller := &apiController{GameServer: gameServer, tokens: tokens}
+	serveMux := gameServer.ServeMux
+
+	serveMux.HandleFunc(GamesRoute, controller.authenticate(controller.HandleGames))
+	serveMux.HandleFunc(GamesRoute+"/", controller.authenticate(controller.HandleGame))
+	serveMux.HandleFunc(ModesRoute, controller.authenticate(controller.HandleModes))
+	serveMux.HandleFunc(OpenAPIRoute, controller.HandleOpenAPI)
+}
+
+func (controller *apiController) HandleGames(w http.ResponseWriter, r *http.Request) {
+	var request createGameRequest
+
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "The request body must be valid JSON")
+		return
+	}
+
+	if request.Challenger == "" || request.Target == "" {
+		writeError(w, http.StatusBadRequest, "Both a challenger and a target are required")
+		return
+	}
+
+	if request.Challenger == request.Target {
+		writeError(w, http.StatusBadRequest, "A player can't challenge themselves")
+		return
+	}
+
+	id, err := controller.GameSessionsManager.CreateModeSession(request.Challenger, request.Target, request.Mode, nil)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	v, _ := controller.GameSessionsManager.Session(id)
+
+	writeJSON(w, http.StatusCreated, newSessionResponse(v))
+}
+
+func (controller *apiController) HandleGame(w http.ResponseWriter, r *http.Request) {
+	tokens := strings.Split(strings.TrimPrefix(r.URL.Path, GamesRoute+"/"), "/")
+	id := tokens[0]
+
+	if len(tokens) == 1 && r.Method == "GET" {
+		controller.getGame(id, w)
+	} else if len(tokens) == 2 && tokens[1] == "moves" && r.Method == "POST" {
+		controller.submitMove(id, w, r)
+	} else if len(tokens) == 2 && tokens[1] == "events" && r.Method == "GET" {
+		controller.streamEvents(id, w, r)
+	} else if len(tokens) <= 2 {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	} else {
+		http.NotFound(w, r)
+	}
+}
+
+func (controller *apiController) HandleModes(w http.ResponseWriter, r *http.Request) {
+	var (
+		names     []string
+		responses []modeResponse
+	)
+
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	for name := range modes.RegisteredGames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		registeredGame := modes.RegisteredGames[name]
+		response := modeResponse{Name: name}
+
+		for _, move := range registeredGame.PreferredOrder {
+			response.Moves = append(response.Moves, moveResponse{
+				Name:    move,
+				Defeats: registeredGame.Moves[move].Defeats,
+			})
+		}
+		responses = append(responses, response)
+	}
+
+	writeJSON(w, http.StatusOK, responses)
+}
+
+func (controller *apiController) HandleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+func (controller *apiController) getGame(id string, w http.ResponseWriter) {
+	v, ok := controller.GameSessionsManager.Session(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrSessionNotFound.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSessionResponse(v))
+}
+
+func (controller *apiController) submitMove(id string, w http.ResponseWriter, r *http.Request) {
+	var request submitMoveRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "The request body must be valid JSON")
+		return
+	}
+
+	v, err := controller.SubmitMove(id, request.Player, request.Move)
+	switch err {
+	case nil:
+		writeJSON(w, http.StatusOK, newSessionResponse(v))
+	case ErrSessionNotFound:
+		writeError(w, http.StatusNotFound, err.Error())
+	case ErrNotSessionPlayer:
+		writeError(w, http.StatusForbidden, err.Error())
+	case ErrInvalidMove:
+		writeError(w, http.StatusBadRequest, err.Error())
+	case ErrMoveLocked, ErrSessionComplete:
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// authenticate rejects requests that don't carry one of the controller's tokens.
+// Browsers can't set headers on WebSocket requests, so the token may also be passed using the access_token query parameter.
+func (controller *apiController) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.URL.Query().Get("access_token")
+		}
+
+		for _, v := range controller.tokens {
+			if v != "" && subtle.ConstantTimeCompare([]byte(token), []byte(v)) == 1 {
+				next(w, r)
+				return
+			}
+		}
+
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "A valid API token is required")
+	}
+}
+
+// newSessionResponse builds the API view of a session.
+// Moves are only revealed once the session has a result, so players can't peek at their opponent's choice.
+func newSessionResponse(v GameSession) sessionResponse {
+	response := sessionResponse{
+		ID:               v.ID,
+		Status:           sessionStatusPending,
+		CreatedAt:        v.Timestamp,
+		Challenger:       v.Challenger,
+		Target:           v.Target,
+		Mode:             v.Mode,
+		ChallengerLocked: v.ChallengerMove != "",
+		TargetLocked:     v.TargetMove != "",
+	}
+
+	if v.Result != "" {
+		response.Status = sessionStatusComplete
+		response.ChallengerMove = v.ChallengerMove
+		response.TargetMove = v.TargetMove
+		response.Result = v.Result
+
+		if v.Result == game.GameStatePlayerOneWins {
+			response.Winner = v.Challenger
+		} else if v.Result == game.GameStatePlayerTwoWins {
+			response.Winner = v.Target
+		}
+	}
+
+	return response
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
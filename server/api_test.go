@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+)
+
+const apiToken = "secret"
+
+func TestAPIRejectsMissingToken(t *testing.T) {
+	gameServer := createMockAPIServer()
+	r := httptest.NewRequest("GET", ModesRoute, nil)
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status %v, got %v", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAPIPlaysAGame(t *testing.T) {
+	var session sessionResponse
+	gameServer := createMockAPIServer()
+
+	w := doAPIRequest(gameServer, "POST", GamesRoute, `{"challenger": "alice", "target": "bob"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %v, got %v: %v", http.StatusCreated, w.Code, w.Body)
+	}
+	json.Unmarshal(w.Body.Bytes(), &session)
+	movesRoute := GamesRoute + "/" + session.ID + "/moves"
+
+	w = doAPIRequest(gameServer, "POST", movesRoute, `{"player": "alice", "move": "rock"}`)
+	json.Unmarshal(w.Body.Bytes(), &session)
+	if session.Status != sessionStatusPending || !session.ChallengerLocked || session.ChallengerMove != "" {
+		t.Fatalf("Challenger's move should be locked in but hidden: %+v", session)
+	}
+
+	w = doAPIRequest(gameServer, "POST", movesRoute, `{"player": "alice", "move": "paper"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected the locked in move to be kept, got status %v", w.Code)
+	}
+
+	doAPIRequest(gameServer, "POST", movesRoute, `{"player": "bob", "move": "scissors"}`)
+	w = doAPIRequest(gameServer, "GET", GamesRoute+"/"+session.ID, "")
+	json.Unmarshal(w.Body.Bytes(), &session)
+	if session.Status != sessionStatusComplete || session.Result != game.GameStatePlayerOneWins || session.Winner != "alice" {
+		t.Fatalf("Expected alice to have won: %+v", session)
+	}
+
+	w = doAPIRequest(gameServer, "POST", movesRoute, `{"player": "bob", "move": "paper"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %v, got %v", http.StatusConflict, w.Code)
+	}
+}
+
+func TestAPIRejectsInvalidMoves(t *testing.T) {
+	var session sessionResponse
+	gameServer := createMockAPIServer()
+	w := doAPIRequest(gameServer, "POST", GamesRoute, `{"challenger": "alice", "target": "bob"}`)
+	json.Unmarshal(w.Body.Bytes(), &session)
+	movesRoute := GamesRoute + "/" + session.ID + "/moves"
+
+	if w := doAPIRequest(gameServer, "POST", movesRoute, `{"player": "alice", "move": "lizard"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %v, got %v", http.StatusBadRequest, w.Code)
+	}
+
+	if w := doAPIRequest(gameServer, "POST", movesRoute, `{"player": "eve", "move": "rock"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status %v, got %v", http.StatusForbidden, w.Code)
+	}
+
+	if w := doAPIRequest(gameServer, "GET", GamesRoute+"/missing", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %v, got %v", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestAPIPlaysSessionModes(t *testing.T) {
+	var session sessionResponse
+	gameServer := createMockAPIServer()
+
+	if w := doAPIRequest(gameServer, "POST", GamesRoute, `{"challenger": "alice", "target": "bob", "mode": "unknown"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %v, got %v", http.StatusBadRequest, w.Code)
+	}
+
+	w := doAPIRequest(gameServer, "POST", GamesRoute, `{"challenger": "alice", "target": "bob", "mode": "rpsls"}`)
+	json.Unmarshal(w.Body.Bytes(), &session)
+	movesRoute := GamesRoute + "/" + session.ID + "/moves"
+
+	doAPIRequest(gameServer, "POST", movesRoute, `{"player": "alice", "move": "spock"}`)
+	w = doAPIRequest(gameServer, "POST", movesRoute, `{"player": "bob", "move": "lizard"}`)
+	json.Unmarshal(w.Body.Bytes(), &session)
+
+	if session.Mode != "rpsls" || session.Result != game.GameStatePlayerTwoWins {
+		t.Fatalf("Expected the session to be played with rpsls: %+v", session)
+	}
+}
+
+func TestAPIListsModes(t *testing.T) {
+	var responses []modeResponse
+	w := doAPIRequest(createMockAPIServer(), "GET", ModesRoute, "")
+	json.Unmarshal(w.Body.Bytes(), &responses)
+
+	if len(responses) != len(modes.RegisteredGames) || responses[0].Moves[0].Name != "rock" {
+		t.Fatalf("Unexpected modes response: %+v", responses)
+	}
+}
+
+func TestAPIServesValidOpenAPISpec(t *testing.T) {
+	var spec map[string]interface{}
+	r := httptest.NewRequest("GET", OpenAPIRoute, nil)
+	w := httptest.NewRecorder()
+	createMockAPIServer().ServeMux.ServeHTTP(w, r)
+
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil || spec["openapi"] == nil {
+		t.Fatalf("OpenAPI spec is not valid JSON: %q", err)
+	}
+}
+
+func createMockAPIServer() *GameServer {
+	gameServer := NewGameServer(modes.StandardGame)
+	gameServer.RegisterAPIRoutes([]string{apiToken})
+
+	return gameServer
+}
+
+func doAPIRequest(gameServer *GameServer, method, route, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, route, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+apiToken)
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, r)
+
+	return w
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"log/slog"
+
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/logging"
+)
+
+// auditBuffer is how many entries can wait to be recorded, further entries are dropped rather than holding up the sessions.
+const auditBuffer = 1024
+
+// Auditor records what happened to sessions, such as audit.FileLog.
+type Auditor interface {
+	Record(entry audit.Entry) error
+}
+
+// EnableAudit records every session's creation, moves, result and expiration with the auditor.
+// Entries are produced while the sessions are locked, so they are recorded in order by a single goroutine instead.
+func (gameServer *GameServer) EnableAudit(auditor Auditor) {
+	gameServer.Auditor = auditor
+	gameServer.auditEntries = make(chan audit.Entry, auditBuffer)
+	go gameServer.recordAudit(auditor, gameServer.auditEntries)
+
+	gameServer.GameSessionsManager.Events.Listen(func(event Event) {
+		v := event.Session
+
+		switch event.Type {
+		case EventCreated:
+			gameServer.audit(audit.Entry{
+				Type:       audit.EntryCreated,
+				Session:    v.ID,
+				Actor:      event.Player,
+				Challenger: v.Challenger,
+				Target:     v.Target,
+				Mode:       v.Mode,
+			})
+		case EventResult:
+			gameServer.audit(audit.Entry{
+				Type:           audit.EntryResult,
+				Session:        v.ID,
+				Challenger:     v.Challenger,
+				Target:         v.Target,
+				ChallengerMove: v.ChallengerMove,
+				TargetMove:     v.TargetMove,
+				Result:         v.Result,
+			})
+		case EventExpired:
+			gameServer.audit(audit.Entry{Type: audit.EntryExpired, Session: v.ID, Actor: event.Player})
+		case EventCancelled:
+			gameServer.audit(audit.Entry{Type: audit.EntryCancelled, Session: v.ID, Actor: event.Player})
+		case EventReplayed:
+			gameServer.audit(audit.Entry{Type: audit.EntryReplayed, Session: v.ID, Actor: event.Player})
+		}
+	})
+}
+
+// audit queues the entry for recordAudit without blocking.
+func (gameServer *GameServer) audit(entry audit.Entry) {
+	if gameServer.auditEntries == nil {
+		return
+	}
+
+	select {
+	case gameServer.auditEntries <- entry:
+	default:
+		slog.Error("Too many audit entries are waiting to be recorded, dropped the entry", logging.SessionKey, entry.Session, "type", entry.Type)
+	}
+}
+
+func (gameServer *GameServer) recordAudit(auditor Auditor, entries <-chan audit.Entry) {
+	for entry := range entries {
+		if err := auditor.Record(entry); err != nil {
+			slog.Error("Failed to record the audit entry", logging.SessionKey, entry.Session, "type", entry.Type, "error", err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/game/modes"
+)
+
+// recordingAuditor sends the recorded entries to the channel.
+type recordingAuditor chan audit.Entry
+
+func (auditor recordingAuditor) Record(entry audit.Entry) error {
+	auditor <- entry
+	return nil
+}
+
+// receive returns the next n entries recorded by the auditor.
+func (auditor recordingAuditor) receive(t *testing.T, n int) []audit.Entry {
+	var entries []audit.Entry
+
+	for len(entries) < n {
+		select {
+		case entry := <-auditor:
+			entries = append(entries, entry)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %v entries, got %+v", n, entries)
+		}
+	}
+
+	return entries
+}
+
+func TestAuditRecordsSessionLifecycle(t *testing.T) {
+	auditor := make(recordingAuditor, 16)
+	gameServer := NewGameServer(modes.StandardGame)
+	gameServer.EnableAudit(auditor)
+
+	id := gameServer.GameSessionsManager.CreateSession("U1", "U2", nil)
+	if _, err := gameServer.SubmitMove(id, "U3", "rock"); err != ErrNotSessionPlayer {
+		t.Fatalf("Expected the move to be rejected, got %v", err)
+	}
+	gameServer.SubmitMove(id, "U1", "rock")
+	gameServer.SubmitMove(id, "U2", "scissors")
+
+	types := []string{audit.EntryCreated, audit.EntryMoveRejected, audit.EntryMove, audit.EntryMove, audit.EntryResult}
+	entries := auditor.receive(t, len(types))
+	for i, entry := range entries {
+		if entry.Type != types[i] || entry.Session != id {
+			t.Fatalf("Expected entry %v to be %v, got %+v", i, types[i], entry)
+		}
+	}
+
+	if rejected := entries[1]; rejected.Actor != "U3" || rejected.Error != ErrNotSessionPlayer.Error() {
+		t.Fatalf("Expected the rejected move to be attributed, got %+v", rejected)
+	}
+	if result := entries[4]; result.ChallengerMove != "rock" || result.TargetMove != "scissors" || result.Result == "" {
+		t.Fatalf("Expected the result to record both moves, got %+v", result)
+	}
+}
@@ -1,12 +1,17 @@
 package server
 
 import (
+	"errors"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/satori/go.uuid"
 
+	"github.com/hamologist/rps/audit"
 	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
 )
 
 // GameServer defines a server/session/game coupling used for simulating games of RPS.
@@ -14,6 +19,10 @@ type GameServer struct {
 	ServeMux            *http.ServeMux
 	GameSessionsManager *SessionManager
 	Game                game.Game
+	Auditor             Auditor // Records what happened to sessions when set, see EnableAudit.
+	auditEntries        chan audit.Entry
+	readinessChecks     map[string]ReadinessCheck
+	readinessMutex      sync.Mutex
 }
 
 // CleanUp is intended to be run in a goroutine.
@@ -26,60 +35,304 @@ func (gameServer *GameServer) CleanUp() {
 	}
 }
 
+// SessionGame returns the game a session is played with, which is the GameServer's Game unless the session has a Mode.
+func (gameServer *GameServer) SessionGame(v GameSession) game.Game {
+	if registeredGame, ok := modes.RegisteredGames[v.Mode]; ok && v.Mode != "" {
+		return registeredGame
+	}
+
+	return gameServer.Game
+}
+
+// SubmitMove locks in a player's move for the session with the provided id.
+// A locked in move can't be changed, once both players have locked in the session's Result is resolved using the session's game.
+// A copy of the updated session is returned.
+// Every submission is recorded by the Auditor, including rejected ones.
+func (gameServer *GameServer) SubmitMove(id, player, move string) (GameSession, error) {
+	sessionManager := gameServer.GameSessionsManager
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	v, err := gameServer.submitMove(id, player, move)
+	if err != nil {
+		gameServer.audit(audit.Entry{Type: audit.EntryMoveRejected, Session: id, Actor: player, Move: move, Error: err.Error()})
+	}
+
+	return v, err
+}
+
+// submitMove locks in the move, the SessionManager must be locked by the caller.
+func (gameServer *GameServer) submitMove(id, player, move string) (GameSession, error) {
+	sessionManager := gameServer.GameSessionsManager
+
+	v, ok := sessionManager.GameSessions[id]
+	if !ok {
+		return GameSession{}, ErrSessionNotFound
+	}
+
+	if v.Result != "" {
+		return v.copy(), ErrSessionComplete
+	}
+
+	rpsGame := gameServer.SessionGame(*v)
+	if _, ok := rpsGame.Moves[move]; !ok {
+		return v.copy(), ErrInvalidMove
+	}
+
+	if player == v.Challenger {
+		if v.ChallengerMove != "" {
+			return v.copy(), ErrMoveLocked
+		}
+		v.ChallengerMove = move
+	} else if player == v.Target {
+		if v.TargetMove != "" {
+			return v.copy(), ErrMoveLocked
+		}
+		sessionManager.Events.Publish(Event{Type: EventAccepted, Player: player, Session: v.copy()})
+		v.TargetMove = move
+	} else {
+		return v.copy(), ErrNotSessionPlayer
+	}
+	gameServer.audit(audit.Entry{Type: audit.EntryMove, Session: id, Actor: player, Move: move})
+	sessionManager.Events.Publish(Event{Type: EventLocked, Player: player, Session: v.copy()})
+
+	if len(v.ChallengerMove) != 0 && len(v.TargetMove) != 0 {
+		result, err := rpsGame.Play(v.ChallengerMove, v.TargetMove)
+		if err != nil {
+			return v.copy(), err
+		}
+		v.Result = result
+		sessionManager.Events.Publish(Event{Type: EventResult, Session: v.copy()})
+	}
+
+	return v.copy(), nil
+}
+
+var (
+	// ErrSessionNotFound is returned when a session doesn't exist or has already expired.
+	ErrSessionNotFound = errors.New("The game session does not exist or has expired")
+	// ErrNotSessionPlayer is returned when a user not associated to a session attempts to submit a move.
+	ErrNotSessionPlayer = errors.New("The user is not associated to the game session")
+	// ErrNotSessionChallenger is returned when a user other than the challenger attempts to cancel a session.
+	ErrNotSessionChallenger = errors.New("Only the challenger can withdraw the challenge")
+	// ErrInvalidMove is returned when a move isn't valid for the session's game.
+	ErrInvalidMove = errors.New("The move is not valid for this game")
+	// ErrMoveLocked is returned when a player submits a move after already locking one in.
+	ErrMoveLocked = errors.New("The move has already been locked in")
+	// ErrSessionComplete is returned when a move is submitted to a session that already has a result.
+	ErrSessionComplete = errors.New("The game session has already been played")
+	// ErrSessionPending is returned when the result of a session that hasn't been played is requested.
+	ErrSessionPending = errors.New("The game session has not been played yet")
+	// ErrUnknownMode is returned when a session is created with a mode that isn't in modes.RegisteredGames.
+	ErrUnknownMode = errors.New("The game mode does not exist")
+)
+
 // SessionManager provides a means of managing sessions needed by the GameServer.
+// GameSessions should only be accessed through the SessionManager's methods, which are safe for concurrent use.
 type SessionManager struct {
 	GameSessions map[string]*GameSession
+	Events       *Hub // Publishes an Event whenever a session changes.
+	mutex        sync.Mutex
 }
 
 // GameSession defines the data used by a game session.
 // GameSession's Data field is intended for storing data specific to a consumer
 // (see the github.com/hamologist/rps/slack package for an example).
 type GameSession struct {
+	ID             string
 	Timestamp      time.Time
 	Challenger     string
 	Target         string
 	ChallengerMove string
 	TargetMove     string
+	Mode           string // The name of one of the modes.RegisteredGames, empty for the GameServer's Game.
+	Result         string // One of the game.GameState constants, set once both players have locked in.
 	Data           map[string]string
 }
 
-// CreateSession creates a session used by the SessionManger.
+// CreateSession creates a session used by the SessionManger, played with the GameServer's Game.
 func (sessionManager *SessionManager) CreateSession(challenger, target string, data map[string]string) string {
+	u, _ := sessionManager.CreateModeSession(challenger, target, "", data)
+	return u
+}
+
+// CreateModeSession creates a session played with one of the modes.RegisteredGames.
+// An empty mode creates a session played with the GameServer's Game.
+func (sessionManager *SessionManager) CreateModeSession(challenger, target, mode string, data map[string]string) (string, error) {
+	if _, ok := modes.RegisteredGames[mode]; !ok && mode != "" {
+		return "", ErrUnknownMode
+	}
+
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
 	gameSessions := sessionManager.GameSessions
 	u := uuid.NewV4().String()
 
 	gameSessions[u] = &GameSession{
+		ID:         u,
 		Timestamp:  time.Now(),
 		Challenger: challenger,
 		Target:     target,
+		Mode:       mode,
 		Data:       data,
 	}
+	sessionManager.Events.Publish(Event{Type: EventCreated, Player: challenger, Session: gameSessions[u].copy()})
 
-	return u
+	return u, nil
+}
+
+// Session returns a copy of the session with the provided id.
+func (sessionManager *SessionManager) Session(id string) (GameSession, bool) {
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	if v, ok := sessionManager.GameSessions[id]; ok {
+		return v.copy(), true
+	}
+
+	return GameSession{}, false
+}
+
+// Sessions returns a copy of every session, oldest first.
+func (sessionManager *SessionManager) Sessions() []GameSession {
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	sessions := make([]GameSession, 0, len(sessionManager.GameSessions))
+	for _, v := range sessionManager.GameSessions {
+		sessions = append(sessions, v.copy())
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Timestamp.Before(sessions[j].Timestamp)
+	})
+
+	return sessions
+}
+
+// SetData sets a consumer specific value in the Data of the session with the provided id.
+func (sessionManager *SessionManager) SetData(id, key, value string) error {
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	v, ok := sessionManager.GameSessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	if v.Data == nil {
+		v.Data = make(map[string]string)
+	}
+	v.Data[key] = value
+
+	return nil
+}
+
+// ExpireSession removes the session with the provided id straight away, as if it had been cleaned up.
+// The actor responsible for expiring the session, such as AdminActor, is published as the event's Player.
+func (sessionManager *SessionManager) ExpireSession(id, actor string) (GameSession, error) {
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	v, ok := sessionManager.GameSessions[id]
+	if !ok {
+		return GameSession{}, ErrSessionNotFound
+	}
+	delete(sessionManager.GameSessions, id)
+
+	if v.Result == "" {
+		sessionManager.Events.Publish(Event{Type: EventExpired, Player: actor, Session: v.copy()})
+	}
+
+	return v.copy(), nil
+}
+
+// CancelSession removes the session with the provided id before it was played.
+// The player is the challenger withdrawing the session, empty when an admin cancels it.
+func (sessionManager *SessionManager) CancelSession(id, player string) (GameSession, error) {
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	v, ok := sessionManager.GameSessions[id]
+	if !ok {
+		return GameSession{}, ErrSessionNotFound
+	}
+	if player != "" && player != v.Challenger {
+		return v.copy(), ErrNotSessionChallenger
+	}
+	if v.Result != "" {
+		return v.copy(), ErrSessionComplete
+	}
+	delete(sessionManager.GameSessions, id)
+
+	sessionManager.Events.Publish(Event{Type: EventCancelled, Player: player, Session: v.copy()})
+
+	return v.copy(), nil
+}
+
+// ReplayResult publishes the result of the session with the provided id again, so it gets posted again.
+// The actor asking for the replay, such as AdminActor, is published as the event's Player.
+func (sessionManager *SessionManager) ReplayResult(id, actor string) (GameSession, error) {
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	v, ok := sessionManager.GameSessions[id]
+	if !ok {
+		return GameSession{}, ErrSessionNotFound
+	}
+	if v.Result == "" {
+		return v.copy(), ErrSessionPending
+	}
+
+	sessionManager.Events.Publish(Event{Type: EventReplayed, Player: actor, Session: v.copy()})
+
+	return v.copy(), nil
 }
 
 // CleanSessions removes all sessions older than 30 minutes.
 // CleanSessions is intended to be invoked by the GameServer's CleanUp method.
 func (sessionManager *SessionManager) CleanSessions() {
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
 	gameSessions := sessionManager.GameSessions
 	for k, v := range gameSessions {
 		if time.Since(v.Timestamp) > (time.Duration(30) * time.Minute) {
 			delete(gameSessions, k)
+
+			if v.Result == "" {
+				sessionManager.Events.Publish(Event{Type: EventExpired, Session: v.copy()})
+			}
 		}
 	}
 }
 
 // NewGameServer creates a GameServer.
-func NewGameServer(game game.Game) *GameServer {
+func NewGameServer(rpsGame game.Game) *GameServer {
 	return &GameServer{
 		ServeMux:            http.NewServeMux(),
 		GameSessionsManager: newSessionManager(),
-		Game:                game,
+		Game:                rpsGame,
 	}
 }
 
 func newSessionManager() *SessionManager {
 	return &SessionManager{
 		GameSessions: make(map[string]*GameSession),
+		Events:       NewHub(),
+	}
+}
+
+// copy returns a copy of the session that can be safely handed out while the original keeps changing.
+func (gameSession *GameSession) copy() GameSession {
+	c := *gameSession
+
+	if gameSession.Data != nil {
+		c.Data = make(map[string]string, len(gameSession.Data))
+		for k, v := range gameSession.Data {
+			c.Data[k] = v
+		}
 	}
+
+	return c
 }
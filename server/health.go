@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	// HealthRoute defines the liveness route, which succeeds as long as the server is serving requests.
+	HealthRoute = "/healthz"
+	// ReadyRoute defines the readiness route, which only succeeds once every ReadinessCheck passes.
+	ReadyRoute = "/readyz"
+
+	// readinessTimeout bounds the time every check has to complete.
+	readinessTimeout = 2 * time.Second
+
+	statusOK = "ok"
+)
+
+// ReadinessCheck reports whether a dependency of the server is ready, returning an error describing why it isn't.
+type ReadinessCheck func(ctx context.Context) error
+
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// AddReadinessCheck adds a check run by the ReadyRoute, such as one verifying the credentials of a chat platform.
+func (gameServer *GameServer) AddReadinessCheck(name string, check ReadinessCheck) {
+	gameServer.readinessMutex.Lock()
+	defer gameServer.readinessMutex.Unlock()
+
+	if gameServer.readinessChecks == nil {
+		gameServer.readinessChecks = make(map[string]ReadinessCheck)
+	}
+	gameServer.readinessChecks[name] = check
+}
+
+// RegisterHealthRoutes registers the HealthRoute and ReadyRoute, used for gating traffic to the server.
+// Readiness always checks that the session store is responsive, along with the checks added through AddReadinessCheck.
+func (gameServer *GameServer) RegisterHealthRoutes() {
+	gameServer.ServeMux.HandleFunc(HealthRoute, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, readinessResponse{Status: statusOK})
+	})
+	gameServer.ServeMux.HandleFunc(ReadyRoute, gameServer.handleReady)
+}
+
+func (gameServer *GameServer) handleReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := map[string]ReadinessCheck{"sessions": gameServer.GameSessionsManager.Ping}
+	gameServer.readinessMutex.Lock()
+	for name, check := range gameServer.readinessChecks {
+		checks[name] = check
+	}
+	gameServer.readinessMutex.Unlock()
+
+	var names []string
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response := readinessResponse{Status: statusOK, Checks: make(map[string]string)}
+	status := http.StatusOK
+
+	for _, name := range names {
+		response.Checks[name] = statusOK
+
+		if err := runCheck(ctx, checks[name]); err != nil {
+			slog.WarnContext(r.Context(), "A readiness check failed", "check", name, "error", err)
+			response.Checks[name] = err.Error()
+			response.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	writeJSON(w, status, response)
+}
+
+// runCheck runs the check, giving up once the context is done even when the check doesn't honour it.
+func runCheck(ctx context.Context, check ReadinessCheck) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- check(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return errors.New("The check did not complete in time")
+	}
+}
+
+// Ping reports whether the session store is responsive, failing when the sessions can't be locked before the context is done.
+func (sessionManager *SessionManager) Ping(ctx context.Context) error {
+	locked := make(chan bool)
+	go func() {
+		sessionManager.mutex.Lock()
+		sessionManager.mutex.Unlock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		return nil
+	case <-ctx.Done():
+		return errors.New("The session store did not respond in time")
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/game/modes"
+)
+
+func getReadiness(gameServer *GameServer, route string) (int, readinessResponse) {
+	var response readinessResponse
+
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, httptest.NewRequest("GET", route, nil))
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	return w.Code, response
+}
+
+func TestHealthRoutes(t *testing.T) {
+	gameServer := NewGameServer(modes.StandardGame)
+	gameServer.RegisterHealthRoutes()
+
+	if status, response := getReadiness(gameServer, HealthRoute); status != http.StatusOK || response.Status != statusOK {
+		t.Fatalf("Expected the server to be live, got %v %+v", status, response)
+	}
+
+	if status, response := getReadiness(gameServer, ReadyRoute); status != http.StatusOK || response.Checks["sessions"] != statusOK {
+		t.Fatalf("Expected the server to be ready, got %v %+v", status, response)
+	}
+
+	gameServer.AddReadinessCheck("slack", func(ctx context.Context) error {
+		return errors.New("invalid_auth")
+	})
+
+	status, response := getReadiness(gameServer, ReadyRoute)
+	if status != http.StatusServiceUnavailable || response.Checks["slack"] != "invalid_auth" || response.Checks["sessions"] != statusOK {
+		t.Fatalf("Expected the failing check to be reported, got %v %+v", status, response)
+	}
+}
+
+func TestPingFailsWhenSessionsAreLocked(t *testing.T) {
+	sessionManager := newSessionManager()
+
+	if err := sessionManager.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sessionManager.mutex.Lock()
+	defer sessionManager.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := sessionManager.Ping(ctx); err == nil {
+		t.Fatal("Expected a locked session store to fail the check")
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// EventCreated is published when a challenge creates a new session.
+	EventCreated = "created"
+	// EventAccepted is published when the target of a challenge first locks in a move.
+	EventAccepted = "accepted"
+	// EventLocked is published whenever a player locks in a move. The move itself is never included.
+	EventLocked = "locked"
+	// EventResult is published once both players have locked in and the session has a result.
+	EventResult = "result"
+	// EventExpired is published when a session is removed before it was played, its Player is set when an admin expired it.
+	EventExpired = "expired"
+	// EventCancelled is published when a session is withdrawn before it was played, by its Player or an admin.
+	EventCancelled = "cancelled"
+	// EventReplayed is published when an admin, the event's Player, asks for the result of a session to be posted again.
+	EventReplayed = "replayed"
+
+	// eventBuffer is the number of events buffered for each subscriber before events are dropped.
+	eventBuffer = 16
+)
+
+// Event describes something that happened to a GameSession.
+type Event struct {
+	Type      string
+	Player    string // The player responsible for the event, if any.
+	Session   GameSession
+	Timestamp time.Time
+}
+
+// Hub fans out session events to subscribers.
+// Subscribers can follow a single session or every session, publishing never blocks on slow subscribers.
+// Listeners are called synchronously for every event, for consumers that can't afford to miss one.
+type Hub struct {
+	mutex       sync.Mutex
+	subscribers map[string]map[chan Event]bool
+	listeners   []func(Event)
+}
+
+// allSessions is the key used for subscribers following every session.
+const allSessions = ""
+
+// NewHub creates a Hub.
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[chan Event]bool),
+	}
+}
+
+// Subscribe returns a channel receiving the events for the session with the provided id.
+// The returned func must be called once the subscriber is done, which closes the channel.
+func (hub *Hub) Subscribe(sessionID string) (<-chan Event, func()) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	events := make(chan Event, eventBuffer)
+	if _, ok := hub.subscribers[sessionID]; !ok {
+		hub.subscribers[sessionID] = make(map[chan Event]bool)
+	}
+	hub.subscribers[sessionID][events] = true
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			hub.unsubscribe(sessionID, events)
+		})
+	}
+}
+
+// SubscribeAll returns a channel receiving the events for every session.
+func (hub *Hub) SubscribeAll() (<-chan Event, func()) {
+	return hub.Subscribe(allSessions)
+}
+
+// Listen registers a func called for every event published to the Hub.
+// Listeners are called while the session is locked, so they must return quickly
+// (starting a goroutine for anything slow) and must not call back into the SessionManager.
+func (hub *Hub) Listen(listener func(Event)) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	hub.listeners = append(hub.listeners, listener)
+}
+
+// Publish sends the event to everyone subscribed to its session, or to every session.
+func (hub *Hub) Publish(event Event) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	for _, listener := range hub.listeners {
+		listener(event)
+	}
+
+	for _, key := range []string{event.Session.ID, allSessions} {
+		for events := range hub.subscribers[key] {
+			select {
+			case events <- event:
+			default:
+			}
+		}
+	}
+}
+
+func (hub *Hub) unsubscribe(sessionID string, events chan Event) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	delete(hub.subscribers[sessionID], events)
+	if len(hub.subscribers[sessionID]) == 0 {
+		delete(hub.subscribers, sessionID)
+	}
+	close(events)
+}
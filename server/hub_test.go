@@ -0,0 +1,101 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/game/modes"
+)
+
+func TestHubDeliversSessionEvents(t *testing.T) {
+	hub := NewHub()
+	events, unsubscribe := hub.Subscribe("one")
+	defer unsubscribe()
+
+	hub.Publish(Event{Type: EventCreated, Session: GameSession{ID: "two"}})
+	hub.Publish(Event{Type: EventCreated, Session: GameSession{ID: "one"}})
+
+	event := receiveEvent(t, events)
+	if event.Session.ID != "one" || event.Timestamp.IsZero() {
+		t.Fatalf("Subscriber received the wrong event: %+v", event)
+	}
+}
+
+func TestHubDeliversAllEvents(t *testing.T) {
+	hub := NewHub()
+	events, unsubscribe := hub.SubscribeAll()
+	defer unsubscribe()
+
+	hub.Publish(Event{Type: EventCreated, Session: GameSession{ID: "one"}})
+	hub.Publish(Event{Type: EventCreated, Session: GameSession{ID: "two"}})
+
+	if receiveEvent(t, events).Session.ID != "one" || receiveEvent(t, events).Session.ID != "two" {
+		t.Fatal("Subscriber to all sessions missed an event")
+	}
+}
+
+func TestHubUnsubscribeClosesChannel(t *testing.T) {
+	hub := NewHub()
+	events, unsubscribe := hub.Subscribe("one")
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-events; ok {
+		t.Fatal("Channel should have been closed")
+	}
+
+	hub.Publish(Event{Type: EventCreated, Session: GameSession{ID: "one"}})
+}
+
+func TestHubDoesNotBlockOnSlowSubscribers(t *testing.T) {
+	hub := NewHub()
+	_, unsubscribe := hub.Subscribe("one")
+	defer unsubscribe()
+
+	for i := 0; i < eventBuffer*2; i++ {
+		hub.Publish(Event{Type: EventLocked, Session: GameSession{ID: "one"}})
+	}
+}
+
+func TestSessionLifecyclePublishesEvents(t *testing.T) {
+	gameServer := NewGameServer(modes.StandardGame)
+	events, unsubscribe := gameServer.GameSessionsManager.Events.SubscribeAll()
+	defer unsubscribe()
+
+	id := gameServer.GameSessionsManager.CreateSession("alice", "bob", nil)
+	gameServer.SubmitMove(id, "alice", "rock")
+	gameServer.SubmitMove(id, "bob", "paper")
+
+	for _, expected := range []string{EventCreated, EventLocked, EventAccepted, EventLocked, EventResult} {
+		if event := receiveEvent(t, events); event.Type != expected {
+			t.Fatalf("Expected a %v event, got %v", expected, event.Type)
+		}
+	}
+}
+
+func receiveEvent(t *testing.T, events <-chan Event) Event {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for an event")
+	}
+
+	return Event{}
+}
+
+func TestHubCallsListeners(t *testing.T) {
+	var received []string
+	hub := NewHub()
+	hub.Listen(func(event Event) {
+		received = append(received, event.Session.ID)
+	})
+
+	for i := 0; i < eventBuffer*2; i++ {
+		hub.Publish(Event{Type: EventLocked, Session: GameSession{ID: "one"}})
+	}
+
+	if len(received) != eventBuffer*2 {
+		t.Fatalf("Listener should have received every event, received %v", len(received))
+	}
+}
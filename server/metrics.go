@@ -0,0 +1,63 @@
+package server
+
+import (
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/metrics"
+)
+
+// MetricsRoute is the route Prometheus scrapes the server's metrics from.
+const MetricsRoute = "/metrics"
+
+var resultLabels = map[string]string{
+	game.GameStatePlayerOneWins: "challenger_wins",
+	game.GameStatePlayerTwoWins: "target_wins",
+	game.GameStateDraw:          "draw",
+}
+
+// RegisterMetricsRoute serves the metrics of the GameServer's sessions on MetricsRoute,
+// along with the metrics of the metrics.DefaultRegistry.
+// Sessions created without a mode are labelled with defaultMode, the name of the GameServer's Game.
+func (gameServer *GameServer) RegisterMetricsRoute(defaultMode string) {
+	registry := metrics.NewRegistry()
+	sessionManager := gameServer.GameSessionsManager
+
+	registry.NewGaugeFunc("rps_sessions_active", "Number of sessions waiting for a result.", func() float64 {
+		var active float64
+		for _, v := range sessionManager.Sessions() {
+			if v.Result == "" {
+				active++
+			}
+		}
+		return active
+	})
+
+	created := registry.NewCounterVec("rps_sessions_created_total", "Number of sessions created.")
+	completed := registry.NewCounterVec("rps_sessions_completed_total", "Number of sessions played to a result.")
+	expired := registry.NewCounterVec("rps_sessions_expired_total", "Number of sessions removed before they were played.")
+	cancelled := registry.NewCounterVec("rps_sessions_cancelled_total", "Number of sessions withdrawn before they were played.")
+	results := registry.NewCounterVec("rps_game_results_total", "Number of games played, by mode and outcome.", "mode", "result")
+	moves := registry.NewCounterVec("rps_moves_total", "Number of moves played in completed games, by mode and move.", "mode", "move")
+
+	sessionManager.Events.Listen(func(event Event) {
+		mode := event.Session.Mode
+		if mode == "" {
+			mode = defaultMode
+		}
+
+		switch event.Type {
+		case EventCreated:
+			created.Inc()
+		case EventExpired:
+			expired.Inc()
+		case EventCancelled:
+			cancelled.Inc()
+		case EventResult:
+			completed.Inc()
+			results.Inc(mode, resultLabels[event.Session.Result])
+			moves.Inc(mode, event.Session.ChallengerMove)
+			moves.Inc(mode, event.Session.TargetMove)
+		}
+	})
+
+	gameServer.ServeMux.Handle(MetricsRoute, metrics.Handler(registry, metrics.DefaultRegistry))
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hamologist/rps/game/modes"
+)
+
+func TestMetricsExportSessionsAndResults(t *testing.T) {
+	gameServer := NewGameServer(modes.StandardGame)
+	gameServer.RegisterMetricsRoute("standard")
+	sessionManager := gameServer.GameSessionsManager
+
+	played := sessionManager.CreateSession("alice", "bob", nil)
+	gameServer.SubmitMove(played, "alice", "rock")
+	gameServer.SubmitMove(played, "bob", "scissors")
+
+	pending, _ := sessionManager.CreateModeSession("alice", "carol", "rpsls", nil)
+	gameServer.SubmitMove(pending, "alice", "spock")
+
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, httptest.NewRequest("GET", MetricsRoute, nil))
+
+	for _, line := range []string{
+		"rps_sessions_active 1",
+		"rps_sessions_created_total 2",
+		"rps_sessions_completed_total 1",
+		"rps_sessions_expired_total 0",
+		`rps_game_results_total{mode="standard",result="challenger_wins"} 1`,
+		`rps_moves_total{mode="standard",move="rock"} 1`,
+		`rps_moves_total{mode="standard",move="scissors"} 1`,
+	} {
+		if !strings.Contains(w.Body.String(), line+"\n") {
+			t.Fatalf("Expected %q in the metrics:\n%v", line, w.Body.String())
+		}
+	}
+
+	if strings.Contains(w.Body.String(), "spock") {
+		t.Fatal("Moves of sessions without a result should not be exported")
+	}
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hamologist/rps/game"
+)
+
+const (
+	// PlayRoute defines the route serving the browser UI.
+	// Challenges are created by posting to PlayRoute, and played from PlayRoute/{id}?key={player key}.
+	PlayRoute = "/play"
+
+	webChallengerName = "challengerName"
+	webKeyPrefix      = "webKey:"
+	webResultWin      = "win"
+	webResultLose     = "lose"
+)
+
+//go:embed web/*.html web/static
+var webAssets embed.FS
+
+var webTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"title": game.DisplayName,
+}).ParseFS(webAssets, "web/*.html"))
+
+type webController struct {
+	*GameServer
+}
+
+type webGamePage struct {
+	ID             string
+	Key            string
+	ShareURL       string
+	ChallengerName string
+	IsChallenger   bool
+	Moves          []string
+	Move           string
+	OpponentLocked bool
+	Result         string
+	OpponentMove   string
+}
+
+// RegisterWebRoutes registers the embedded browser UI on the GameServer's ServeMux.
+// Players are identified by a secret key included in their game link rather than an account,
+// so anyone with the challenge link can play.
+// Sessions created by other front ends can be played in the browser by handing out links from PlayerLink.
+func (gameServer *GameServer) RegisterWebRoutes() {
+	controller := &webController{GameServer: gameServer}
+	// Only the static directory is served, the templates stay private.
+	static, _ := fs.Sub(webAssets, "web/static")
+	serveMux := gameServer.ServeMux
+
+	serveMux.HandleFunc(PlayRoute, controller.HandleIndex)
+	serveMux.HandleFunc(PlayRoute+"/", controller.HandleGame)
+	serveMux.Handle(PlayRoute+"/static/", http.StripPrefix(PlayRoute+"/static/", http.FileServer(http.FS(static))))
+}
+
+func (controller *webController) HandleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "GET" {
+		controller.render(w, r, "index.html", nil)
+	} else if r.Method == "POST" {
+		controller.createChallenge(w, r)
+	} else {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (controller *webController) HandleGame(w http.ResponseWriter, r *http.Request) {
+	tokens := strings.Split(strings.TrimPrefix(r.URL.Path, PlayRoute+"/"), "/")
+	id := tokens[0]
+
+	if len(tokens) == 1 && r.Method == "GET" {
+		controller.showGame(id, w, r)
+	} else if len(tokens) == 2 && tokens[1] == "move" && r.Method == "POST" {
+		controller.submitMove(id, w, r)
+	} else {
+		http.NotFound(w, r)
+	}
+}
+
+func (controller *webController) createChallenge(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.PostFormValue("name"))
+	if name == "" {
+		name = "Anonymous"
+	}
+
+	challengerKey, err := newPlayerKey()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to create a player key", "error", err)
+		http.Error(w, "An error occurred while setting up the game.", http.StatusInternalServerError)
+		return
+	}
+
+	targetKey, err := newPlayerKey()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to create a player key", "error", err)
+		http.Error(w, "An error occurred while setting up the game.", http.StatusInternalServerError)
+		return
+	}
+
+	id := controller.GameSessionsManager.CreateSession(challengerKey, targetKey, map[string]string{
+		webChallengerName:            name,
+		webKeyPrefix + challengerKey: challengerKey,
+		webKeyPrefix + targetKey:     targetKey,
+	})
+
+	http.Redirect(w, r, gameLink(id, challengerKey), http.StatusSeeOther)
+}
+
+func (controller *webController) showGame(id string, w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	v, ok := controller.GameSessionsManager.Session(id)
+
+	if !ok {
+		http.Error(w, "This game doesn't exist. Maybe the game session has expired.", http.StatusNotFound)
+		return
+	}
+
+	player, ok := playerForKey(v, key)
+	isChallenger := player == v.Challenger
+	if !ok {
+		http.Error(w, "This link isn't valid for the game.", http.StatusForbidden)
+		return
+	}
+
+	page := webGamePage{
+		ID:             v.ID,
+		Key:            key,
+		ChallengerName: v.Data[webChallengerName],
+		IsChallenger:   isChallenger,
+		Moves:          controller.SessionGame(v).PreferredOrder,
+		Move:           v.TargetMove,
+		OpponentLocked: v.ChallengerMove != "",
+		OpponentMove:   v.ChallengerMove,
+	}
+
+	if isChallenger {
+		// Sessions created through the browser UI use the target's key as their id, so the challenger is shown the target's link to share.
+		// Other front ends send each player their own link, built with PlayerLink.
+		if target, _ := playerForKey(v, v.Target); target == v.Target {
+			page.ShareURL = requestOrigin(r) + gameLink(v.ID, v.Target)
+		}
+		page.Move = v.ChallengerMove
+		page.OpponentLocked = v.TargetMove != ""
+		page.OpponentMove = v.TargetMove
+	}
+
+	if v.Result == "" {
+		page.OpponentMove = ""
+	} else if v.Result == game.GameStateDraw {
+		page.Result = game.GameStateDraw
+	} else if (v.Result == game.GameStatePlayerOneWins) == isChallenger {
+		page.Result = webResultWin
+	} else {
+		page.Result = webResultLose
+	}
+
+	controller.render(w, r, "game.html", page)
+}
+
+func (controller *webController) submitMove(id string, w http.ResponseWriter, r *http.Request) {
+	key := r.PostFormValue("key")
+	v, ok := controller.GameSessionsManager.Session(id)
+
+	if !ok {
+		http.Error(w, "This game doesn't exist. Maybe the game session has expired.", http.StatusNotFound)
+		return
+	}
+
+	player, ok := playerForKey(v, key)
+	if !ok {
+		http.Error(w, "This link isn't valid for the game.", http.StatusForbidden)
+		return
+	}
+
+	_, err := controller.SubmitMove(id, player, r.PostFormValue("move"))
+	if err != nil && err != ErrMoveLocked && err != ErrSessionComplete {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, r, gameLink(id, key), http.StatusSeeOther)
+}
+
+func (controller *webController) render(w http.ResponseWriter, r *http.Request, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := webTemplates.ExecuteTemplate(w, name, data); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to render the page", "template", name, "error", err)
+	}
+}
+
+// PlayerLink returns a link to the browser UI that lets the player submit their move for the session.
+// The link is relative to wherever the GameServer is being served from.
+func (gameServer *GameServer) PlayerLink(id, player string) (string, error) {
+	key, err := newPlayerKey()
+	if err != nil {
+		return "", err
+	}
+
+	if err := gameServer.GameSessionsManager.SetData(id, webKeyPrefix+key, player); err != nil {
+		return "", err
+	}
+
+	return gameLink(id, key), nil
+}
+
+// playerForKey returns the player that the key was handed out to for the session.
+func playerForKey(v GameSession, key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+
+	player, ok := v.Data[webKeyPrefix+key]
+	return player, ok
+}
+
+func gameLink(id, key string) string {
+	return PlayRoute + "/" + url.PathEscape(id) + "?key=" + url.QueryEscape(key)
+}
+
+func requestOrigin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
+
+func newPlayerKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
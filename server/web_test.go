@@ -0,0 +1,131 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hamologist/rps/game/modes"
+)
+
+func TestWebPlaysAGame(t *testing.T) {
+	gameServer := createMockWebServer()
+
+	w := doWebRequest(gameServer, "POST", PlayRoute, url.Values{"name": {"Alice"}})
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("Expected status %v, got %v", http.StatusSeeOther, w.Code)
+	}
+	challengerLink := w.Header().Get("Location")
+
+	w = doWebRequest(gameServer, "GET", challengerLink, nil)
+	body := w.Body.String()
+	for _, move := range modes.StandardGame.PreferredOrder {
+		if !strings.Contains(body, `value="`+move+`"`) {
+			t.Fatalf("Game page is missing a button for %v", move)
+		}
+	}
+
+	share := body[strings.Index(body, "http://example.com"+PlayRoute):]
+	targetLink := strings.TrimPrefix(share[:strings.Index(share, `"`)], "http://example.com")
+	id, challengerKey := parseGameLink(t, challengerLink)
+	_, targetKey := parseGameLink(t, targetLink)
+
+	w = doWebRequest(gameServer, "GET", targetLink, nil)
+	if !strings.Contains(w.Body.String(), "Alice has challenged you") {
+		t.Fatalf("Target should see who challenged them:\n%v", w.Body.String())
+	}
+
+	doWebRequest(gameServer, "POST", PlayRoute+"/"+id+"/move", url.Values{"key": {challengerKey}, "move": {"paper"}})
+	w = doWebRequest(gameServer, "GET", challengerLink, nil)
+	if !strings.Contains(w.Body.String(), "Waiting for your opponent") {
+		t.Fatalf("Challenger should be waiting for their opponent:\n%v", w.Body.String())
+	}
+
+	doWebRequest(gameServer, "POST", PlayRoute+"/"+id+"/move", url.Values{"key": {targetKey}, "move": {"rock"}})
+	if w = doWebRequest(gameServer, "GET", challengerLink, nil); !strings.Contains(w.Body.String(), "You won!") {
+		t.Fatalf("Challenger should have won:\n%v", w.Body.String())
+	}
+
+	if w = doWebRequest(gameServer, "GET", targetLink, nil); !strings.Contains(w.Body.String(), "You lost.") {
+		t.Fatalf("Target should have lost:\n%v", w.Body.String())
+	}
+}
+
+func TestWebRejectsUnknownKeys(t *testing.T) {
+	gameServer := createMockWebServer()
+	w := doWebRequest(gameServer, "POST", PlayRoute, url.Values{"name": {"Alice"}})
+	id, _ := parseGameLink(t, w.Header().Get("Location"))
+
+	if w := doWebRequest(gameServer, "GET", gameLink(id, "wrong"), nil); w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status %v, got %v", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestWebIgnoresSessionsFromOtherFrontEnds(t *testing.T) {
+	gameServer := createMockWebServer()
+	id := gameServer.GameSessionsManager.CreateSession("alice", "bob", nil)
+
+	if w := doWebRequest(gameServer, "GET", gameLink(id, "alice"), nil); w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status %v, got %v", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestWebPlaysSessionsFromPlayerLinks(t *testing.T) {
+	gameServer := createMockWebServer()
+	id := gameServer.GameSessionsManager.CreateSession("alice", "bob", map[string]string{webChallengerName: "Alice"})
+	link, err := gameServer.PlayerLink(id, "bob")
+
+	if err != nil {
+		t.Fatalf("PlayerLink should not have caused an error: %q", err)
+	}
+
+	if w := doWebRequest(gameServer, "GET", link, nil); !strings.Contains(w.Body.String(), "Alice has challenged you") {
+		t.Fatalf("Player link should show the challenge:\n%v", w.Body.String())
+	}
+
+	_, key := parseGameLink(t, link)
+	doWebRequest(gameServer, "POST", PlayRoute+"/"+id+"/move", url.Values{"key": {key}, "move": {"rock"}})
+
+	if v, _ := gameServer.GameSessionsManager.Session(id); v.TargetMove != "rock" {
+		t.Fatalf("Move should have been submitted for bob: %+v", v)
+	}
+}
+
+func TestWebServesStaticAssets(t *testing.T) {
+	if w := doWebRequest(createMockWebServer(), "GET", PlayRoute+"/static/style.css", nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %v, got %v", http.StatusOK, w.Code)
+	}
+}
+
+func TestWebDoesNotServeTemplates(t *testing.T) {
+	if w := doWebRequest(createMockWebServer(), "GET", PlayRoute+"/static/game.html", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %v, got %v", http.StatusNotFound, w.Code)
+	}
+}
+
+func createMockWebServer() *GameServer {
+	gameServer := NewGameServer(modes.StandardGame)
+	gameServer.RegisterWebRoutes()
+
+	return gameServer
+}
+
+func doWebRequest(gameServer *GameServer, method, route string, form url.Values) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, route, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, r)
+
+	return w
+}
+
+func parseGameLink(t *testing.T, link string) (string, string) {
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Invalid game link %q: %q", link, err)
+	}
+
+	return strings.TrimPrefix(u.Path, PlayRoute+"/"), u.Query().Get("key")
+}
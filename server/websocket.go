@@ -0,0 +1,104 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/hamologist/rps/logging"
+)
+
+const (
+	// EventSnapshot is sent to WebSocket clients as soon as they connect, describing the session's current state.
+	EventSnapshot = "snapshot"
+
+	writeWait  = 10 * time.Second
+	pingPeriod = 30 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Requests have already been authenticated with an API token, so cross origin clients are allowed.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type eventMessage struct {
+	Type      string          `json:"type"`
+	Player    string          `json:"player,omitempty"`
+	Session   sessionResponse `json:"session"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// streamEvents upgrades the request to a WebSocket and pushes the session's events to the client.
+// The connection is closed by the server once the session has a result or has expired.
+func (controller *apiController) streamEvents(id string, w http.ResponseWriter, r *http.Request) {
+	events, unsubscribe := controller.GameSessionsManager.Events.Subscribe(id)
+	defer unsubscribe()
+
+	v, ok := controller.GameSessionsManager.Session(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrSessionNotFound.Error())
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Failed to upgrade to a websocket", logging.SessionKey, id, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if !writeEvent(conn, Event{Type: EventSnapshot, Session: v, Timestamp: time.Now()}) || v.Result != "" {
+		return
+	}
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if !writeEvent(conn, event) {
+				return
+			}
+
+			if event.Type == EventResult || event.Type == EventExpired || event.Type == EventCancelled {
+				conn.SetWriteDeadline(time.Now().Add(writeWait))
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, event.Type))
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func writeEvent(conn *websocket.Conn, event Event) bool {
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	err := conn.WriteJSON(eventMessage{
+		Type:      event.Type,
+		Player:    event.Player,
+		Session:   newSessionResponse(event.Session),
+		Timestamp: event.Timestamp,
+	})
+
+	return err == nil
+}
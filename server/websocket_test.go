@@ -0,0 +1,91 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWebSocketStreamsSessionEvents(t *testing.T) {
+	gameServer := createMockAPIServer()
+	testServer := httptest.NewServer(gameServer.ServeMux)
+	defer testServer.Close()
+
+	id := gameServer.GameSessionsManager.CreateSession("alice", "bob", nil)
+	conn := dialEvents(t, testServer, id, apiToken)
+	defer conn.Close()
+
+	if message := readEventMessage(t, conn); message.Type != EventSnapshot || message.Session.ID != id {
+		t.Fatalf("Expected a snapshot of the session, got %+v", message)
+	}
+
+	gameServer.SubmitMove(id, "alice", "rock")
+	gameServer.SubmitMove(id, "bob", "scissors")
+
+	for _, expected := range []string{EventLocked, EventAccepted, EventLocked} {
+		if message := readEventMessage(t, conn); message.Type != expected || message.Session.ChallengerMove != "" {
+			t.Fatalf("Expected a %v event without moves, got %+v", expected, message)
+		}
+	}
+
+	message := readEventMessage(t, conn)
+	if message.Type != EventResult || message.Session.Winner != "alice" {
+		t.Fatalf("Expected a result event, got %+v", message)
+	}
+
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Fatalf("Expected the server to close the connection, got %v", err)
+	}
+}
+
+func TestWebSocketRequiresToken(t *testing.T) {
+	gameServer := createMockAPIServer()
+	testServer := httptest.NewServer(gameServer.ServeMux)
+	defer testServer.Close()
+
+	id := gameServer.GameSessionsManager.CreateSession("alice", "bob", nil)
+	_, resp, err := websocket.DefaultDialer.Dial(eventsURL(testServer, id, "wrong"), nil)
+
+	if err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatal("WebSocket connection should have been rejected")
+	}
+}
+
+func TestWebSocketRejectsUnknownSession(t *testing.T) {
+	testServer := httptest.NewServer(createMockAPIServer().ServeMux)
+	defer testServer.Close()
+
+	_, resp, err := websocket.DefaultDialer.Dial(eventsURL(testServer, "missing", apiToken), nil)
+
+	if err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatal("WebSocket connection should have been rejected")
+	}
+}
+
+func dialEvents(t *testing.T, testServer *httptest.Server, id, token string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(eventsURL(testServer, id, token), nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to the events WebSocket: %q", err)
+	}
+
+	return conn
+}
+
+func eventsURL(testServer *httptest.Server, id, token string) string {
+	return "ws" + strings.TrimPrefix(testServer.URL, "http") + GamesRoute + "/" + id + "/events?access_token=" + token
+}
+
+func readEventMessage(t *testing.T, conn *websocket.Conn) eventMessage {
+	var message eventMessage
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("Failed to read an event: %q", err)
+	}
+
+	return message
+}
@@ -1,25 +1,89 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/config"
+	"github.com/hamologist/rps/discord"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/logging"
+	"github.com/hamologist/rps/metrics"
+	"github.com/hamologist/rps/rpc"
+	"github.com/hamologist/rps/server"
 	"github.com/hamologist/rps/slack"
+	"github.com/hamologist/rps/teams"
 )
 
-// DefaultPort defines the default application port that will be used on startup
-const DefaultPort = ":8081"
+func main() {
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.Debug))
 
-var applicationPort = ":" + os.Getenv("RPS_PORT")
+	gameServer := server.NewGameServer(modes.RegisteredGames[cfg.Game])
 
-func main() {
-	go slack.DefaultGameServer.CleanUp()
-	log.Fatal(http.ListenAndServe(applicationPort, slack.DefaultGameServer.ServeMux))
-}
+	if len(cfg.APITokens) != 0 {
+		gameServer.RegisterAPIRoutes(cfg.APITokens)
+	} else {
+		slog.Warn("No API tokens were provided, the REST API will be disabled", "env", "RPS_API_TOKENS")
+	}
+
+	if len(cfg.AdminTokens) != 0 {
+		gameServer.RegisterAdminRoutes(cfg.AdminTokens)
+	}
+
+	gameServer.RegisterWebRoutes()
+	gameServer.RegisterMetricsRoute(cfg.Game)
+	gameServer.RegisterHealthRoutes()
+
+	// The history command is disabled unless auditing is.
+	var history audit.Reader
+	if cfg.AuditFile != "" {
+		auditLog, err := audit.OpenFile(cfg.AuditFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer auditLog.Close()
 
-func init() {
-	if applicationPort == ":" {
-		applicationPort = DefaultPort
+		gameServer.EnableAudit(auditLog)
+		history = auditLog
 	}
+
+	if err := slack.RegisterRoutes(gameServer, cfg, history); err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.Discord.PublicKey != "" {
+		publicKey, _ := cfg.Discord.Key()
+		discord.RegisterRoutes(gameServer, publicKey)
+	}
+
+	if cfg.Teams.Secret != "" {
+		if err := teams.RegisterRoutes(gameServer, cfg); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			log.Fatal(rpc.NewGRPCServer(gameServer, cfg.APITokens).Serve(listener))
+		}()
+	}
+
+	go gameServer.CleanUp()
+	log.Fatal(http.ListenAndServe(cfg.Addr(), logging.Middleware(metrics.InstrumentMux(gameServer.ServeMux))))
 }
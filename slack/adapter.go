@@ -0,0 +1,266 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/logging"
+	"github.com/hamologist/rps/server"
+)
+
+// Platform is the chat platform name stored with sessions created through Slack.
+const Platform = "slack"
+
+// adapter implements chat.Adapter for Slack slash commands and interactive messages.
+// With legacyAttachments, interactive message attachments are used instead of Block Kit.
+type adapter struct {
+	clients           ClientFactory
+	apiURL            string // The configured Web API base URL, see validResponseURL.
+	legacyAttachments bool
+}
+
+func (adapter *adapter) Platform() string {
+	return Platform
+}
+
+func (adapter *adapter) ParseCommand(r *http.Request) (chat.Command, error) {
+	var body Body
+
+	err := r.ParseForm()
+	if err != nil {
+		slog.WarnContext(r.Context(), "Failed to parse the slash command", "error", err)
+		return chat.Command{}, errors.New("An error occurred while proccessing your response")
+	}
+
+	logRequest(r)
+	err = decoder.Decode(&body, r.PostForm)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Failed to decode the slash command", "error", err)
+	}
+
+	return commandFromBody(adapter.clients, body)
+}
+
+// commandFromBody parses the challenge issued by a slash command, looking up the user being challenged.
+func commandFromBody(clients ClientFactory, body Body) (chat.Command, error) {
+	workspace := workspaceKey(body.EnterpriseID, body.TeamID)
+	client, err := clients(workspace)
+	if err != nil {
+		return chat.Command{}, err
+	}
+
+	target, mode, err := parseChallenge(client, strings.Fields(body.Text))
+	if err != nil {
+		return chat.Command{}, err
+	}
+	if target.ID == body.UserID {
+		return chat.Command{}, errors.New("You need to challenge another person.")
+	}
+
+	return chat.Command{
+		Challenger: chat.Player{ID: body.UserID, Name: body.UserName},
+		Target:     target,
+		Channel:    body.ChannelID,
+		Workspace:  workspace,
+		Mode:       mode,
+	}, nil
+}
+
+// parseChallenge parses the mention of the user being challenged, followed by an optional game mode.
+func parseChallenge(client SlackClient, textTokens []string) (chat.Player, string, error) {
+	var (
+		target string
+		mode   string
+		err    error
+	)
+
+	if len(textTokens) >= 1 {
+		target = textTokens[0]
+	}
+
+	if len(textTokens) >= 2 {
+		if mode, err = chat.ParseMode(textTokens[1]); err != nil {
+			return chat.Player{}, "", err
+		}
+	}
+
+	target = strings.TrimSuffix(strings.Split(strings.Replace(target, "<@", "", 1), "|")[0], ">")
+	targetInfo, err := client.GetUserInfo(target)
+	if apiError, ok := err.(*APIError); ok && apiError.Code != "user_not_found" {
+		slog.Error("Failed to look up the challenged user", logging.UserKey, target, "error", err)
+		return chat.Player{}, "", errors.New(apiError.UserMessage())
+	}
+	if err != nil {
+		return chat.Player{}, "", errors.New(
+			"The user you are attempting to challenge isn't a valid Slack user for this team.\n" +
+				"Make sure you are using the \"@\" mention syntax.",
+		)
+	}
+
+	return chat.Player{ID: target, Name: targetInfo.Name}, mode, nil
+}
+
+// RenderChallenge leaves the slash command response empty, both players are prompted with ephemeral messages instead.
+func (adapter *adapter) RenderChallenge(w http.ResponseWriter, challenge chat.Challenge) error {
+	return nil
+}
+
+func (adapter *adapter) SendPrivatePrompt(challenge chat.Challenge, player chat.Player) error {
+	text := fmt.Sprintf("@%v has challenged you to a game of RPS.", challenge.Challenger.Name)
+	if player.ID == challenge.Challenger.ID {
+		text = fmt.Sprintf("The challenge was submitted to @%v. They are now selecting a move.", challenge.Target.Name)
+	}
+
+	client, err := adapter.clients(challenge.Workspace)
+	if err != nil {
+		return err
+	}
+
+	// Only the challenger can withdraw the challenge, so only their prompt has a Cancel button.
+	cancellable := player.ID == challenge.Challenger.ID
+	message := Message{Text: text, Blocks: challengeBlocks(challenge, text, cancellable)}
+	if adapter.legacyAttachments {
+		message = Message{Text: text, Attachments: challengeAttachments(challenge, cancellable)}
+	}
+
+	return client.PostEphemeral(challenge.Channel, player.ID, message)
+}
+
+func (adapter *adapter) PostResult(session server.GameSession) error {
+	command, ok := chat.SessionCommand(Platform, session)
+	if !ok {
+		return errors.New("Game session does not support Slack.")
+	}
+
+	client, err := adapter.clients(command.Workspace)
+	if err != nil {
+		return err
+	}
+
+	playResult := chat.FormatResult(session, func(player chat.Player) string {
+		return "@" + player.Name
+	})
+
+	message := Message{Text: playResult, Blocks: resultBlocks(command, session)}
+	if adapter.legacyAttachments {
+		message = Message{Text: playResult}
+	}
+
+	// Replaces the move buttons of both players, so they can't be clicked once the game was played.
+	for _, player := range []string{session.Challenger, session.Target} {
+		if responseURL, ok := session.Data[responseURLKeyPrefix+player]; ok && validResponseURL(responseURL, adapter.apiURL) {
+			if err := client.UpdateMessage(responseURL, message); err != nil {
+				slog.Error("Failed to replace the player's message", logging.SessionKey, session.ID, logging.UserKey, player, "error", err)
+			}
+		}
+	}
+
+	return client.PostMessage(command.Channel, message)
+}
+
+// challengeBlocks renders the move selection prompt sent to a player, cancellable prompts also get a button withdrawing the challenge.
+func challengeBlocks(challenge chat.Challenge, text string, cancellable bool) []Block {
+	var buttons []*ButtonElement
+
+	for _, move := range challenge.Moves {
+		button := NewButtonElement(moveActionPrefix+move, game.DisplayName(move), encodePayloadValue(challenge.SessionID, move))
+		button.Confirm = NewConfirmDialog(
+			"Lock in your move",
+			fmt.Sprintf("Play *%v*? You won't be able to change your move afterwards.", move),
+			"Lock in",
+			"Cancel",
+		)
+		buttons = append(buttons, button)
+	}
+
+	if cancellable {
+		button := NewButtonElement(cancelActionID, "Cancel", encodePayloadValue(challenge.SessionID, ""))
+		button.Style = ButtonStyleDanger
+		button.Confirm = NewConfirmDialog(
+			"Withdraw your challenge",
+			fmt.Sprintf("Withdraw your challenge to *@%v*?", challenge.Target.Name),
+			"Withdraw",
+			"Keep playing",
+		)
+		buttons = append(buttons, button)
+	}
+
+	return []Block{
+		NewSectionBlock(text+"\nPlease select your move", nil),
+		NewActionsBlock(moveBlockID, buttons...),
+		NewContextBlock("Moves stay hidden until both players have locked in.", sessionText(challenge.SessionID)),
+	}
+}
+
+// challengeAttachments renders the move selection prompt using legacy interactive message attachments.
+func challengeAttachments(challenge chat.Challenge, cancellable bool) []Attachment {
+	var slackAttachmentActions []AttachmentAction
+
+	for _, move := range challenge.Moves {
+		slackAttachmentActions = append(slackAttachmentActions, AttachmentAction{
+			Name:  "move",
+			Text:  game.DisplayName(move),
+			Type:  "button",
+			Value: encodePayloadValue(challenge.SessionID, move),
+		})
+	}
+
+	if cancellable {
+		slackAttachmentActions = append(slackAttachmentActions, AttachmentAction{
+			Name:  cancelActionID,
+			Text:  "Cancel",
+			Style: ButtonStyleDanger,
+			Type:  "button",
+			Value: encodePayloadValue(challenge.SessionID, ""),
+			Confirm: &ConfirmButton{
+				Title:       "Withdraw your challenge",
+				Text:        fmt.Sprintf("Withdraw your challenge to @%v?", challenge.Target.Name),
+				OkText:      "Withdraw",
+				DismissText: "Keep playing",
+			},
+		})
+	}
+
+	return []Attachment{
+		Attachment{
+			Text:           "Please select your move",
+			Fallback:       "You are unable to choose a move",
+			CallbackID:     "player_move_selection",
+			Color:          "#3AA3E3",
+			AttachmentType: "default",
+			Actions:        slackAttachmentActions,
+		},
+	}
+}
+
+// resultBlocks renders the result of a completed session.
+func resultBlocks(command chat.Command, session server.GameSession) []Block {
+	playResult := chat.FormatResult(session, func(player chat.Player) string {
+		return "*@" + player.Name + "*"
+	})
+
+	return []Block{
+		NewSectionBlock(":trophy: "+playResult, nil),
+		NewContextBlock(fmt.Sprintf("Challenge issued by @%v", command.Challenger.Name), sessionText(session.ID)),
+	}
+}
+
+// sessionText shows the session's ID, which admins look up the history of sessions with.
+func sessionText(id string) string {
+	return fmt.Sprintf("Session `%v`", id)
+}
+
+func encodePayloadValue(sessionID, move string) string {
+	jsonData, _ := json.Marshal(payloadValue{
+		SessionID: sessionID,
+		Move:      move,
+	})
+
+	return string(jsonData)
+}
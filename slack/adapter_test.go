@@ -0,0 +1,71 @@
+package slack
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+var mockCommand = chat.Command{
+	Challenger: chat.Player{ID: "U1", Name: "alice"},
+	Target:     chat.Player{ID: "U2", Name: "bob"},
+	Channel:    "C1",
+}
+
+func TestChallengeBlocks(t *testing.T) {
+	challenge := chat.Challenge{Command: mockCommand, SessionID: "session", Moves: modes.StandardGame.PreferredOrder}
+	blocks := challengeBlocks(challenge, "@alice has challenged you to a game of RPS.", false)
+
+	actions, ok := blocks[1].(*ActionsBlock)
+	if !ok || actions.BlockType() != BlockTypeActions || len(actions.Elements) != len(challenge.Moves) {
+		t.Fatalf("Expected an actions block with a button for each move, got %+v", blocks[1])
+	}
+
+	var value payloadValue
+	button := actions.Elements[0]
+	json.Unmarshal([]byte(button.Value), &value)
+
+	if button.Text.Text != "Rock" || button.Confirm == nil || value.SessionID != "session" || value.Move != "rock" {
+		t.Fatalf("Unexpected move button: %+v", button)
+	}
+
+	js, err := json.Marshal(blocks)
+	if err != nil {
+		t.Fatalf("Failed to marshal blocks: %q", err)
+	}
+
+	var decoded []map[string]interface{}
+	json.Unmarshal(js, &decoded)
+	for i, blockType := range []string{BlockTypeSection, BlockTypeActions, BlockTypeContext} {
+		if decoded[i]["type"] != blockType {
+			t.Fatalf("Expected block %v to be a %v block, got %v", i, blockType, decoded[i]["type"])
+		}
+	}
+}
+
+func TestResultBlocks(t *testing.T) {
+	session := server.GameSession{
+		ID:             "session",
+		Challenger:     "U1",
+		Target:         "U2",
+		ChallengerMove: "rock",
+		TargetMove:     "scissors",
+		Result:         game.GameStatePlayerOneWins,
+		Data:           chat.CreateSessionData(Platform, mockCommand),
+	}
+
+	blocks := resultBlocks(mockCommand, session)
+	section := blocks[0].(*SectionBlock)
+	if section.Text.Text != ":trophy: *@alice* defeated *@bob*, rock beats scissors" {
+		t.Fatalf("Unexpected result: %v", section.Text.Text)
+	}
+
+	// The session's ID is shown for looking up its history.
+	if context := blocks[1].(*ContextBlock); context.Elements[1].Text != "Session `session`" {
+		t.Fatalf("Expected the session's ID to be shown, got %+v", context.Elements[1])
+	}
+}
@@ -2,8 +2,25 @@
 package slack
 
 const (
-	// PostEphemeralRoute is the route used for posting ephermal messages.
-	PostEphemeralRoute = "https://slack.com/api/chat.postEphemeral"
+	// DefaultBaseURL is the base URL of the Slack Web API.
+	DefaultBaseURL = "https://slack.com/api"
+	// responseURLHost is the host of the response_urls Slack sends with commands and interactions.
+	responseURLHost = "hooks.slack.com"
+
+	// PostEphemeralMethod is the Web API method used for posting ephermal messages.
+	PostEphemeralMethod = "chat.postEphemeral"
+	// PostMessageMethod is the Web API method used for posting messages to a channel.
+	PostMessageMethod = "chat.postMessage"
+	// UsersInfoMethod is the Web API method used for looking up a user.
+	UsersInfoMethod = "users.info"
+	// ViewsOpenMethod is the Web API method used for opening a modal.
+	ViewsOpenMethod = "views.open"
+	// ViewsPublishMethod is the Web API method used for publishing a user's App Home tab.
+	ViewsPublishMethod = "views.publish"
+	// AuthTestMethod is the Web API method used for verifying a token.
+	AuthTestMethod = "auth.test"
+	// OAuthAccessMethod is the Web API method used for exchanging an OAuth code for a bot token.
+	OAuthAccessMethod = "oauth.v2.access"
 )
 
 // Body provides a way to structure and work with the standard slack /command response.
@@ -30,6 +47,7 @@ type PayloadResponse struct {
 
 // Payload is used for unmarshalling data from a SlackPayloadResponse.
 type Payload struct {
+	Type            string          `json:"type"`
 	Actions         []PayloadAction `json:"actions"`
 	CallbackID      string          `json:"string"`
 	Team            Team            `json:"team"`
@@ -43,19 +61,45 @@ type Payload struct {
 	ResponseURL     string          `json:"response_url"`
 	IsAppUnfurl     string          `json:"is_app_unfurl"`
 	TriggerID       string          `json:"trigger_id"`
+	View            *PayloadView    `json:"view,omitempty"`
+}
+
+// PayloadView defines the modal a view_submission payload was sent from.
+type PayloadView struct {
+	ID              string    `json:"id"`
+	CallbackID      string    `json:"callback_id"`
+	PrivateMetadata string    `json:"private_metadata"`
+	State           ViewState `json:"state"`
+}
+
+// ViewState holds the values of a submitted modal's inputs, keyed by block_id then action_id.
+type ViewState struct {
+	Values map[string]map[string]ViewStateValue `json:"values"`
+}
+
+// ViewStateValue defines the value of a single input.
+type ViewStateValue struct {
+	Type           string        `json:"type"`
+	SelectedUser   string        `json:"selected_user,omitempty"`
+	SelectedOption *OptionObject `json:"selected_option,omitempty"`
 }
 
 // PayloadAction defines data about the action that was performed.
+// Interactive messages identify the action using its Name, while Block Kit uses ActionID and BlockID.
 type PayloadAction struct {
-	Name  string `schema:"name"`
-	Value string `schema:"value"`
-	Type  string `schema:"type"`
+	Name     string `schema:"name"`
+	Value    string `schema:"value"`
+	Type     string `schema:"type"`
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
 }
 
 // Team defines data about the slack team the action was executed with.
 type Team struct {
-	ID     string `schema:"id"`
-	Domain string `schema:"domain"`
+	ID           string `schema:"id"`
+	Domain       string `schema:"domain"`
+	Name         string `schema:"name"`
+	EnterpriseID string `schema:"enterprise_id" json:"enterprise_id"` // Only set for workspaces of an Enterprise Grid organization.
 }
 
 // Channel defines data about the channel the action was executed in.
@@ -75,9 +119,11 @@ type OriginalMessage struct{}
 
 // Response is used to send either a in_channel or ephemeral response to a given user.
 type Response struct {
-	ResponseType string       `json:"response_type"`
-	Text         string       `json:"text"`
-	Attachments  []Attachment `json:"attachments,omitempty"`
+	ResponseType    string       `json:"response_type"`
+	Text            string       `json:"text"`
+	Attachments     []Attachment `json:"attachments,omitempty"`
+	Blocks          []Block      `json:"blocks,omitempty"`
+	ReplaceOriginal bool         `json:"replace_original,omitempty"`
 }
 
 // Attachment allows a Response to define message areas and setup Actions on a Response.
@@ -116,6 +162,62 @@ type PostEphemeralPayload struct {
 	User        string `schema:"user"`
 	AsUser      bool   `schema:"as_user,omitempty"`
 	Attachments string `schema:"attachments,omitempty"`
+	Blocks      string `schema:"blocks,omitempty"`
 	LinkNames   bool   `schema:"link_names,omitempty"`
 	Parse       string `schema:"parse,omitempty"`
 }
+
+// PostMessagePayload contains the payload data needed for using the postMessage API endpoint.
+// Text is used as the notification and fallback text for messages with Blocks.
+type PostMessagePayload struct {
+	Token       string `schema:"token"`
+	Channel     string `schema:"channel"`
+	Text        string `schema:"text"`
+	Blocks      string `schema:"blocks,omitempty"`
+	Attachments string `schema:"attachments,omitempty"`
+}
+
+// APIResponse is the envelope every Slack Web API method responds with.
+type APIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ViewsOpenPayload contains the payload data needed for using the views.open API endpoint.
+type ViewsOpenPayload struct {
+	TriggerID string `json:"trigger_id"`
+	View      *View  `json:"view"`
+}
+
+// ViewSubmissionResponse is used to respond to a view_submission payload, such as for showing errors next to inputs.
+type ViewSubmissionResponse struct {
+	ResponseAction string            `json:"response_action"`
+	Errors         map[string]string `json:"errors,omitempty"`
+}
+
+// EventCallback provides a way to structure and work with the requests sent by the Events API.
+// URL verification requests only carry a Challenge, while event callbacks carry the Event.
+type EventCallback struct {
+	Token        string `json:"token"`
+	TeamID       string `json:"team_id"`
+	EnterpriseID string `json:"enterprise_id"`
+	Type         string `json:"type"`
+	Challenge    string `json:"challenge,omitempty"`
+	Event        Event  `json:"event"`
+}
+
+// Event defines the event an EventCallback was sent for.
+type Event struct {
+	Type    string `json:"type"`
+	User    string `json:"user"`
+	Text    string `json:"text,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Tab     string `json:"tab,omitempty"`
+	TS      string `json:"ts,omitempty"`
+}
+
+// ViewsPublishPayload contains the payload data needed for using the views.publish API endpoint.
+type ViewsPublishPayload struct {
+	UserID string `json:"user_id"`
+	View   *View  `json:"view"`
+}
@@ -0,0 +1,225 @@
+package slack
+
+const (
+	// BlockTypeSection displays text, optionally alongside an accessory element.
+	BlockTypeSection = "section"
+	// BlockTypeActions holds interactive elements such as buttons.
+	BlockTypeActions = "actions"
+	// BlockTypeContext displays small, secondary text.
+	BlockTypeContext = "context"
+	// BlockTypeInput collects a value in a modal.
+	BlockTypeInput = "input"
+
+	// ElementTypeButton is an interactive button element.
+	ElementTypeButton = "button"
+	// ElementTypeUsersSelect is a select menu listing the workspace's users.
+	ElementTypeUsersSelect = "users_select"
+	// ElementTypeStaticSelect is a select menu listing the provided options.
+	ElementTypeStaticSelect = "static_select"
+
+	// ViewTypeModal is a view displayed as a modal.
+	ViewTypeModal = "modal"
+	// ViewTypeHome is a view displayed in the App Home tab.
+	ViewTypeHome = "home"
+
+	// TextTypePlain is a text object without any formatting.
+	TextTypePlain = "plain_text"
+	// TextTypeMarkdown is a text object formatted using Slack's mrkdwn.
+	TextTypeMarkdown = "mrkdwn"
+
+	// ButtonStylePrimary is the green button style.
+	ButtonStylePrimary = "primary"
+	// ButtonStyleDanger is the red button style.
+	ButtonStyleDanger = "danger"
+)
+
+// Block is implemented by every Block Kit layout block.
+type Block interface {
+	BlockType() string
+}
+
+// TextObject defines text displayed by blocks and elements.
+type TextObject struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// SectionBlock displays text, with an optional accessory element.
+type SectionBlock struct {
+	Type      string         `json:"type"`
+	BlockID   string         `json:"block_id,omitempty"`
+	Text      *TextObject    `json:"text"`
+	Accessory *ButtonElement `json:"accessory,omitempty"`
+}
+
+// ActionsBlock holds interactive elements.
+type ActionsBlock struct {
+	Type     string           `json:"type"`
+	BlockID  string           `json:"block_id,omitempty"`
+	Elements []*ButtonElement `json:"elements"`
+}
+
+// ContextBlock displays secondary text below the rest of a message.
+type ContextBlock struct {
+	Type     string        `json:"type"`
+	BlockID  string        `json:"block_id,omitempty"`
+	Elements []*TextObject `json:"elements"`
+}
+
+// InputBlock collects a value in a modal using its Element.
+type InputBlock struct {
+	Type    string         `json:"type"`
+	BlockID string         `json:"block_id,omitempty"`
+	Label   *TextObject    `json:"label"`
+	Element *SelectElement `json:"element"`
+}
+
+// SelectElement is a select menu. Users select menus list users instead of Options.
+type SelectElement struct {
+	Type          string          `json:"type"`
+	ActionID      string          `json:"action_id"`
+	Placeholder   *TextObject     `json:"placeholder,omitempty"`
+	Options       []*OptionObject `json:"options,omitempty"`
+	InitialOption *OptionObject   `json:"initial_option,omitempty"`
+}
+
+// OptionObject defines a single option of a SelectElement.
+type OptionObject struct {
+	Text  *TextObject `json:"text"`
+	Value string      `json:"value"`
+}
+
+// View defines a modal, opened using the views.open API method, or an App Home tab, published using views.publish.
+// PrivateMetadata is sent back with the view_submission payload.
+type View struct {
+	Type            string      `json:"type"`
+	CallbackID      string      `json:"callback_id,omitempty"`
+	Title           *TextObject `json:"title,omitempty"`
+	Submit          *TextObject `json:"submit,omitempty"`
+	Close           *TextObject `json:"close,omitempty"`
+	Blocks          []Block     `json:"blocks"`
+	PrivateMetadata string      `json:"private_metadata,omitempty"`
+}
+
+// ButtonElement is an interactive button. Its ActionID and Value are sent back in block_actions payloads.
+type ButtonElement struct {
+	Type     string         `json:"type"`
+	Text     *TextObject    `json:"text"`
+	ActionID string         `json:"action_id"`
+	Value    string         `json:"value,omitempty"`
+	Style    string         `json:"style,omitempty"`
+	Confirm  *ConfirmDialog `json:"confirm,omitempty"`
+}
+
+// ConfirmDialog asks a user to confirm before a button's action is sent.
+type ConfirmDialog struct {
+	Title   *TextObject `json:"title"`
+	Text    *TextObject `json:"text"`
+	Confirm *TextObject `json:"confirm"`
+	Deny    *TextObject `json:"deny"`
+}
+
+// BlockType returns the section block type.
+func (block *SectionBlock) BlockType() string {
+	return block.Type
+}
+
+// BlockType returns the actions block type.
+func (block *ActionsBlock) BlockType() string {
+	return block.Type
+}
+
+// BlockType returns the context block type.
+func (block *ContextBlock) BlockType() string {
+	return block.Type
+}
+
+// BlockType returns the input block type.
+func (block *InputBlock) BlockType() string {
+	return block.Type
+}
+
+// NewTextObject creates a TextObject of the provided type.
+func NewTextObject(textType, text string) *TextObject {
+	return &TextObject{
+		Type: textType,
+		Text: text,
+	}
+}
+
+// NewSectionBlock creates a SectionBlock displaying mrkdwn text.
+func NewSectionBlock(text string, accessory *ButtonElement) *SectionBlock {
+	return &SectionBlock{
+		Type:      BlockTypeSection,
+		Text:      NewTextObject(TextTypeMarkdown, text),
+		Accessory: accessory,
+	}
+}
+
+// NewActionsBlock creates an ActionsBlock holding the provided elements.
+func NewActionsBlock(blockID string, elements ...*ButtonElement) *ActionsBlock {
+	return &ActionsBlock{
+		Type:     BlockTypeActions,
+		BlockID:  blockID,
+		Elements: elements,
+	}
+}
+
+// NewContextBlock creates a ContextBlock displaying each text as mrkdwn.
+func NewContextBlock(texts ...string) *ContextBlock {
+	block := &ContextBlock{Type: BlockTypeContext}
+	for _, text := range texts {
+		block.Elements = append(block.Elements, NewTextObject(TextTypeMarkdown, text))
+	}
+
+	return block
+}
+
+// NewButtonElement creates a ButtonElement with a plain text label.
+func NewButtonElement(actionID, text, value string) *ButtonElement {
+	return &ButtonElement{
+		Type:     ElementTypeButton,
+		Text:     NewTextObject(TextTypePlain, text),
+		ActionID: actionID,
+		Value:    value,
+	}
+}
+
+// NewConfirmDialog creates a ConfirmDialog, the text is displayed as mrkdwn.
+func NewConfirmDialog(title, text, confirm, deny string) *ConfirmDialog {
+	return &ConfirmDialog{
+		Title:   NewTextObject(TextTypePlain, title),
+		Text:    NewTextObject(TextTypeMarkdown, text),
+		Confirm: NewTextObject(TextTypePlain, confirm),
+		Deny:    NewTextObject(TextTypePlain, deny),
+	}
+}
+
+// NewInputBlock creates an InputBlock collecting a value using the element.
+func NewInputBlock(blockID, label string, element *SelectElement) *InputBlock {
+	return &InputBlock{
+		Type:    BlockTypeInput,
+		BlockID: blockID,
+		Label:   NewTextObject(TextTypePlain, label),
+		Element: element,
+	}
+}
+
+// NewSelectElement creates a SelectElement of the provided type, listing the options.
+func NewSelectElement(selectType, actionID, placeholder string, options ...*OptionObject) *SelectElement {
+	return &SelectElement{
+		Type:        selectType,
+		ActionID:    actionID,
+		Placeholder: NewTextObject(TextTypePlain, placeholder),
+		Options:     options,
+	}
+}
+
+// NewOptionObject creates an OptionObject with a plain text label.
+func NewOptionObject(text, value string) *OptionObject {
+	return &OptionObject{
+		Text:  NewTextObject(TextTypePlain, text),
+		Value: value,
+	}
+}
@@ -0,0 +1,444 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamologist/rps/metrics"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 250 * time.Millisecond
+	// defaultMaxRetryWait is the longest Retry-After a call waits for, calls rate limited for longer fail straight away.
+	defaultMaxRetryWait = 10 * time.Second
+	// apiTimeout bounds every call made to Slack, so a hung call can't hold up a work queue worker.
+	apiTimeout = 10 * time.Second
+)
+
+// httpClient is shared by every call made to Slack.
+var httpClient = &http.Client{Timeout: apiTimeout}
+
+// idempotentMethods are the Web API methods retried after a server error, as calling them twice has no further effect.
+// Other methods, such as chat.postMessage, may have been processed before Slack failed, so retrying could post twice.
+var idempotentMethods = map[string]bool{
+	UsersInfoMethod:    true,
+	AuthTestMethod:     true,
+	ViewsPublishMethod: true,
+}
+
+// ErrNotInstalled is returned when a request comes from a workspace the app hasn't been installed in.
+var ErrNotInstalled = errors.New("The RPS app has not been installed in this workspace.")
+
+// SlackClient is the subset of the Slack Web API used by the controller, on behalf of a single workspace.
+type SlackClient interface {
+	GetUserInfo(user string) (User, error)
+	PostEphemeral(channel, user string, message Message) error
+	PostMessage(channel string, message Message) error
+	// UpdateMessage replaces the message an interaction was taken on, using the interaction's response_url.
+	UpdateMessage(responseURL string, message Message) error
+	// PostResponse posts an ephemeral follow-up to a slash command, using the command's response_url.
+	PostResponse(responseURL string, message Message) error
+	OpenView(triggerID string, view *View) error
+	PublishView(user string, view *View) error
+	// AuthTest verifies the client's token is valid.
+	AuthTest() error
+}
+
+// ClientFactory returns the SlackClient for the workspace with the provided workspaceKey.
+type ClientFactory func(workspace string) (SlackClient, error)
+
+// Message defines the content of a message sent through a SlackClient.
+// Text is used as the notification and fallback text when Blocks or Attachments are provided.
+type Message struct {
+	Text        string
+	Blocks      []Block
+	Attachments []Attachment
+}
+
+// APIError is returned when Slack rejects a call, either through the HTTP status or an ok:false response.
+type APIError struct {
+	Method     string
+	StatusCode int
+	Code       string        // The error code of an ok:false response, such as "channel_not_found".
+	RetryAfter time.Duration // How long Slack asked to wait before retrying a rate limited call.
+}
+
+func (err *APIError) Error() string {
+	if err.Code != "" {
+		return fmt.Sprintf("Slack responded to %v with an error: %v", err.Method, err.Code)
+	}
+
+	return fmt.Sprintf("Slack responded to %v with status %v", err.Method, err.StatusCode)
+}
+
+// Temporary reports whether the call may succeed when retried.
+func (err *APIError) Temporary() bool {
+	switch err.Code {
+	case "ratelimited", "internal_error", "fatal_error", "service_unavailable", "request_timeout":
+		return true
+	}
+
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= http.StatusInternalServerError
+}
+
+// UserMessage explains the error to the user who triggered the call.
+func (err *APIError) UserMessage() string {
+	switch err.Code {
+	case "channel_not_found", "not_in_channel", "is_archived":
+		return "RPS can't post in this channel. Please invite the app to the channel and try again."
+	case "user_not_found", "user_not_in_channel":
+		return "The user couldn't be found in this channel."
+	case "invalid_auth", "not_authed", "token_revoked", "account_inactive":
+		return "RPS is no longer authorized for this workspace. Please reinstall the app."
+	case "expired_trigger_id":
+		return "The request took too long. Please try again."
+	}
+
+	if err.Temporary() {
+		return "Slack is having trouble right now. Please try again in a moment."
+	}
+
+	return "Slack rejected the request. Please try again."
+}
+
+type apiClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration // The wait before the first retry, doubled for every following retry.
+	maxWait    time.Duration
+	sleep      func(time.Duration)
+}
+
+// NewClient creates a SlackClient calling the Web API methods found at baseURL (usually DefaultBaseURL) using the token.
+func NewClient(baseURL, token string) SlackClient {
+	return &apiClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultRetryBackoff,
+		maxWait:    defaultMaxRetryWait,
+		sleep:      time.Sleep,
+	}
+}
+
+// NewClientFactory creates a ClientFactory using the tokens of the workspaces the app was installed in.
+// Workspaces without an installation fall back to the defaultToken, when one was provided.
+func NewClientFactory(baseURL string, tokens TokenStore, defaultToken string) ClientFactory {
+	return func(workspace string) (SlackClient, error) {
+		if token, ok := tokens.Token(workspace); ok {
+			return NewClient(baseURL, token), nil
+		}
+
+		if defaultToken == "" {
+			return nil, ErrNotInstalled
+		}
+
+		return NewClient(baseURL, defaultToken), nil
+	}
+}
+
+func (client *apiClient) GetUserInfo(user string) (User, error) {
+	var response struct {
+		APIResponse
+		User User `json:"user"`
+	}
+
+	err := client.postForm(UsersInfoMethod, url.Values{"token": {client.token}, "user": {user}}, &response)
+	return response.User, err
+}
+
+func (client *apiClient) PostEphemeral(channel, user string, message Message) error {
+	blocks, attachments, err := encodeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	err = encoder.Encode(PostEphemeralPayload{
+		Token:       client.token,
+		Channel:     channel,
+		Text:        message.Text,
+		User:        user,
+		Blocks:      blocks,
+		Attachments: attachments,
+	}, form)
+	if err != nil {
+		return err
+	}
+
+	return client.postForm(PostEphemeralMethod, form, &APIResponse{})
+}
+
+func (client *apiClient) PostMessage(channel string, message Message) error {
+	blocks, attachments, err := encodeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	err = encoder.Encode(PostMessagePayload{
+		Token:       client.token,
+		Channel:     channel,
+		Text:        message.Text,
+		Blocks:      blocks,
+		Attachments: attachments,
+	}, form)
+	if err != nil {
+		return err
+	}
+
+	return client.postForm(PostMessageMethod, form, &APIResponse{})
+}
+
+func (client *apiClient) UpdateMessage(responseURL string, message Message) error {
+	return client.postResponse(responseURL, message, true)
+}
+
+func (client *apiClient) PostResponse(responseURL string, message Message) error {
+	return client.postResponse(responseURL, message, false)
+}
+
+// validResponseURL reports whether a response_url can be posted to, as it comes from a request rather than from Slack's API.
+// Only https URLs on responseURLHost are accepted, or URLs on the host of the configured Web API base URL (apiURL),
+// for servers standing in for Slack.
+func validResponseURL(responseURL, apiURL string) bool {
+	u, err := url.Parse(responseURL)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "https" && u.Host == responseURLHost {
+		return true
+	}
+
+	api, err := url.Parse(apiURL)
+	return apiURL != "" && err == nil && u.Scheme == api.Scheme && u.Host == api.Host
+}
+
+func (client *apiClient) postResponse(responseURL string, message Message, replaceOriginal bool) error {
+	body, err := json.Marshal(Response{
+		ResponseType:    ephemeralResponse,
+		Text:            message.Text,
+		Blocks:          message.Blocks,
+		Attachments:     message.Attachments,
+		ReplaceOriginal: replaceOriginal,
+	})
+	if err != nil {
+		return err
+	}
+
+	return client.do("response_url", func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", responseURL, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, err
+	}, nil)
+}
+
+func (client *apiClient) OpenView(triggerID string, view *View) error {
+	return client.postJSON(ViewsOpenMethod, ViewsOpenPayload{TriggerID: triggerID, View: view})
+}
+
+func (client *apiClient) PublishView(user string, view *View) error {
+	return client.postJSON(ViewsPublishMethod, ViewsPublishPayload{UserID: user, View: view})
+}
+
+func (client *apiClient) AuthTest() error {
+	return client.postForm(AuthTestMethod, url.Values{"token": {client.token}}, &APIResponse{})
+}
+
+// postForm calls a Web API method with a form, decoding the response into result.
+func (client *apiClient) postForm(method string, form url.Values, result apiResult) error {
+	body := form.Encode()
+
+	return client.do(method, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", client.baseURL+"/"+method, strings.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		return req, err
+	}, result)
+}
+
+// postJSON calls a Web API method accepting JSON.
+func (client *apiClient) postJSON(method string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return client.do(method, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", client.baseURL+"/"+method, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			req.Header.Set("Authorization", "Bearer "+client.token)
+		}
+		return req, err
+	}, &APIResponse{})
+}
+
+// do sends the request created by newRequest, decoding the response into result when one is provided.
+// Rate limited calls, temporary failures of idempotentMethods and requests that were never sent are retried with an exponential backoff, waiting longer when Slack asks to through Retry-After.
+func (client *apiClient) do(method string, newRequest func() (*http.Request, error), result apiResult) error {
+	backoff := client.backoff
+
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		started := time.Now()
+		resp, err := client.httpClient.Do(req)
+		if err == nil {
+			err = readAPIResponse(method, resp, result)
+			resp.Body.Close()
+		}
+		observeAPICall(method, time.Since(started), err)
+
+		wait := backoff
+		switch err := err.(type) {
+		case nil:
+			return nil
+		case *APIError:
+			if !retryable(method, err) || err.RetryAfter > client.maxWait {
+				return err
+			}
+			if err.RetryAfter > wait {
+				wait = err.RetryAfter
+			}
+		case *url.Error:
+			// Requests without a response may have been processed, so only those that were never sent are retried,
+			// making sure messages aren't posted twice.
+			if !requestNotSent(err) {
+				return err
+			}
+		default:
+			return err
+		}
+
+		if attempt >= client.maxRetries {
+			return err
+		}
+
+		client.sleep(wait)
+		backoff *= 2
+	}
+}
+
+// retryable reports whether the failed call can be retried.
+// Rate limited calls were never processed, while other temporary errors are only retried for idempotentMethods.
+func retryable(method string, err *APIError) bool {
+	if err.StatusCode == http.StatusTooManyRequests || err.Code == "ratelimited" {
+		return true
+	}
+
+	return err.Temporary() && idempotentMethods[method]
+}
+
+// requestNotSent reports whether the request failed before reaching Slack, such as when the connection couldn't be made.
+func requestNotSent(err *url.Error) bool {
+	var opError *net.OpError
+	return errors.As(err, &opError) && opError.Op == "dial"
+}
+
+var (
+	apiCallDuration = metrics.DefaultRegistry.NewHistogramVec(
+		"rps_slack_api_duration_seconds",
+		"Latency of the calls made to Slack, by Web API method (or response_url), including failed attempts.",
+		metrics.DefaultBuckets,
+		"method",
+	)
+	apiCallErrors = metrics.DefaultRegistry.NewCounterVec(
+		"rps_slack_api_errors_total",
+		"Number of failed calls made to Slack, by Web API method and error code.",
+		"method", "code",
+	)
+)
+
+func observeAPICall(method string, duration time.Duration, err error) {
+	apiCallDuration.Observe(duration.Seconds(), method)
+
+	switch err := err.(type) {
+	case nil:
+	case *APIError:
+		code := err.Code
+		if code == "" {
+			code = "http_" + strconv.Itoa(err.StatusCode)
+		}
+		apiCallErrors.Inc(method, code)
+	case *url.Error:
+		apiCallErrors.Inc(method, "network")
+	default:
+		apiCallErrors.Inc(method, "invalid_response")
+	}
+}
+
+// apiResult is implemented by every Web API response, through the embedded APIResponse.
+type apiResult interface {
+	apiResponse() APIResponse
+}
+
+func (response APIResponse) apiResponse() APIResponse {
+	return response
+}
+
+// readAPIResponse returns an APIError when Slack didn't respond ok, either through the status or the response's ok field.
+// A nil result only checks the status, for endpoints that don't respond with JSON.
+func readAPIResponse(method string, resp *http.Response, result apiResult) error {
+	if resp.StatusCode != http.StatusOK {
+		seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+
+		return &APIError{
+			Method:     method,
+			StatusCode: resp.StatusCode,
+			RetryAfter: time.Duration(seconds) * time.Second,
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return err
+	}
+
+	if apiResponse := result.apiResponse(); !apiResponse.OK {
+		return &APIError{Method: method, StatusCode: resp.StatusCode, Code: apiResponse.Error}
+	}
+
+	return nil
+}
+
+func encodeMessage(message Message) (blocks, attachments string, err error) {
+	if len(message.Blocks) != 0 {
+		js, err := json.Marshal(message.Blocks)
+		if err != nil {
+			return "", "", err
+		}
+		blocks = string(js)
+	}
+
+	if len(message.Attachments) != 0 {
+		js, err := json.Marshal(message.Attachments)
+		if err != nil {
+			return "", "", err
+		}
+		attachments = string(js)
+	}
+
+	return blocks, attachments, nil
+}
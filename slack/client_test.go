@@ -0,0 +1,148 @@
+package slack
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient creates a client for a server responding with the responses in order, recording the waits between retries.
+func newTestClient(responses ...func(w http.ResponseWriter)) (*apiClient, *[]time.Duration, *httptest.Server) {
+	var waits []time.Duration
+	calls := 0
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		responses[calls](w)
+		calls++
+	}))
+
+	client := NewClient(testServer.URL, "xoxb-test").(*apiClient)
+	client.sleep = func(wait time.Duration) {
+		waits = append(waits, wait)
+	}
+
+	return client, &waits, testServer
+}
+
+func respondOK(w http.ResponseWriter) {
+	fmt.Fprint(w, `{"ok":true}`)
+}
+
+func respondStatus(status int, retryAfter string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"ok":false,"error":"ratelimited"}`)
+	}
+}
+
+func TestClientHonoursRetryAfter(t *testing.T) {
+	client, waits, testServer := newTestClient(respondStatus(http.StatusTooManyRequests, "2"), respondOK)
+	defer testServer.Close()
+
+	if err := client.PostMessage("C1", Message{Text: "hi"}); err != nil {
+		t.Fatalf("Expected the call to succeed once retried, got %q", err)
+	}
+
+	if len(*waits) != 1 || (*waits)[0] != 2*time.Second {
+		t.Fatalf("Expected a single wait of 2s, got %v", *waits)
+	}
+}
+
+func TestClientRetriesWithExponentialBackoff(t *testing.T) {
+	failure := respondStatus(http.StatusServiceUnavailable, "")
+	client, waits, testServer := newTestClient(failure, failure, failure, failure)
+	defer testServer.Close()
+
+	_, err := client.GetUserInfo("U1")
+	if apiError, ok := err.(*APIError); !ok || apiError.StatusCode != http.StatusServiceUnavailable || !apiError.Temporary() {
+		t.Fatalf("Expected a temporary APIError, got %#v", err)
+	}
+
+	expected := []time.Duration{defaultRetryBackoff, 2 * defaultRetryBackoff, 4 * defaultRetryBackoff}
+	if fmt.Sprint(*waits) != fmt.Sprint(expected) {
+		t.Fatalf("Expected waits of %v, got %v", expected, *waits)
+	}
+}
+
+func TestClientDoesNotRetryPermanentErrors(t *testing.T) {
+	client, waits, testServer := newTestClient(
+		func(w http.ResponseWriter) { fmt.Fprint(w, `{"ok":false,"error":"not_in_channel"}`) },
+	)
+	defer testServer.Close()
+
+	errors := apiCallErrors.Value(PostMessageMethod, "not_in_channel")
+	err := client.PostMessage("C1", Message{Text: "hi"})
+	apiError, ok := err.(*APIError)
+	if !ok || apiError.Code != "not_in_channel" || apiError.Method != PostMessageMethod || len(*waits) != 0 {
+		t.Fatalf("Expected a single failed call, got %#v after %v", err, *waits)
+	}
+
+	if apiCallErrors.Value(PostMessageMethod, "not_in_channel") != errors+1 {
+		t.Fatal("Expected the error to be counted")
+	}
+
+	if apiError.UserMessage() != "RPS can't post in this channel. Please invite the app to the channel and try again." {
+		t.Fatalf("Unexpected user message: %q", apiError.UserMessage())
+	}
+}
+
+func TestClientDoesNotRepostAfterServerErrors(t *testing.T) {
+	client, waits, testServer := newTestClient(respondStatus(http.StatusInternalServerError, ""))
+	defer testServer.Close()
+
+	if err := client.PostMessage("C1", Message{Text: "hi"}); err == nil || len(*waits) != 0 {
+		t.Fatalf("Expected a message that may have been posted not to be retried, got %q after %v", err, *waits)
+	}
+}
+
+func TestClientGivesUpOnLongRetryAfter(t *testing.T) {
+	client, waits, testServer := newTestClient(respondStatus(http.StatusTooManyRequests, "60"))
+	defer testServer.Close()
+
+	err := client.UpdateMessage(testServer.URL, Message{Text: "hi"})
+	if apiError, ok := err.(*APIError); !ok || apiError.RetryAfter != time.Minute || len(*waits) != 0 {
+		t.Fatalf("Expected the call to fail without waiting, got %#v after %v", err, *waits)
+	}
+}
+
+func TestClientOnlyRetriesRequestsThatWereNotSent(t *testing.T) {
+	client, waits, testServer := newTestClient(func(w http.ResponseWriter) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	})
+	defer testServer.Close()
+
+	if err := client.PostMessage("C1", Message{Text: "hi"}); err == nil || len(*waits) != 0 {
+		t.Fatalf("Expected a request dropped by the server not to be retried, got %q after %v", err, *waits)
+	}
+
+	testServer.Close()
+	if err := client.PostMessage("C1", Message{Text: "hi"}); err == nil || len(*waits) != client.maxRetries {
+		t.Fatalf("Expected a refused connection to be retried, got %q after %v", err, *waits)
+	}
+}
+
+func TestValidResponseURLOnlyAcceptsSlack(t *testing.T) {
+	for responseURL, valid := range map[string]bool{
+		"https://hooks.slack.com/actions/T1/1/abc": true,
+		"http://hooks.slack.com/actions/T1/1/abc":  false,
+		"https://hooks.slack.com.example.com/":     false,
+		"https://169.254.169.254/latest/meta-data": false,
+		"http://127.0.0.1:8080/response":           true,
+		"http://127.0.0.1:9090/response":           false,
+		"":                                         false,
+	} {
+		if validResponseURL(responseURL, "http://127.0.0.1:8080/api") != valid {
+			t.Fatalf("Expected %q to be valid: %v", responseURL, valid)
+		}
+	}
+
+	if validResponseURL("http://127.0.0.1:8080/response", "") {
+		t.Fatal("Expected only Slack's host to be accepted without a configured API URL")
+	}
+}
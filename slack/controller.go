@@ -1,27 +1,40 @@
 package slack
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"io/ioutil"
+	"log/slog"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
-	"os"
 	"strings"
+	"time"
 
-	"github.com/nlopes/slack"
+	"github.com/gorilla/schema"
 
-	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/config"
+	"github.com/hamologist/rps/logging"
 	"github.com/hamologist/rps/server"
-	"github.com/gorilla/schema"
+)
+
+const (
+	blockActionsPayload  = "block_actions"
+	responseURLKeyPrefix = "slackResponseURL:"
+	moveBlockID          = "move_selection"
+	moveActionPrefix     = "move_"
+	cancelActionID       = "cancel_challenge"
+	cancelCommand        = "cancel" // The slash command's first word for withdrawing the user's pending challenge.
+	busyText             = "RPS is busy right now. Please try again in a moment."
 )
 
 var (
-	commandName string
-	debug       bool
-	decoder     = schema.NewDecoder()
-	encoder     = schema.NewEncoder()
+	decoder = schema.NewDecoder()
+	encoder = schema.NewEncoder()
 )
 
 type payloadValue struct {
@@ -30,38 +43,119 @@ type payloadValue struct {
 }
 
 type controller struct {
-	*server.GameServer
+	*chat.Service
+	clients       ClientFactory
+	queue         *workQueue
+	signingSecret string
+	apiURL        string // The configured Web API base URL, see validResponseURL.
+	history       audit.Reader
+	admins        []string
+	defaultMode   string // The name of the GameServer's Game, see formatEntry.
 }
 
+// HandleGameRequest issues a challenge from the slash command's text, or withdraws the user's pending challenge with "cancel".
+// Without any text, a modal is opened for setting up the challenge instead.
+// The command is acknowledged straight away and processed on the work queue, errors are sent through the response_url.
+// Once a signing secret is configured, only commands signed by Slack are accepted.
 func (controller *controller) HandleGameRequest(w http.ResponseWriter, r *http.Request) {
+	var body Body
+
+	if r.Method != "POST" {
+		return
+	}
+
+	verified := controller.verifyRequest(r)
+	if controller.signingSecret != "" && !verified {
+		http.Error(w, "The request could not be verified", http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		slog.WarnContext(r.Context(), "Failed to parse the slash command", "error", err)
+		fmt.Fprint(w, "An error occurred while proccessing your response")
+		return
+	}
+
+	logRequest(r)
+	if err := decoder.Decode(&body, r.PostForm); err != nil {
+		slog.WarnContext(r.Context(), "Failed to decode the slash command", "error", err)
+	}
+
+	// The job runs once the request has been acknowledged, so it only keeps the request's logging attributes.
+	ctx := logging.With(logging.Detach(r.Context()), slog.String(logging.TeamKey, body.TeamID), slog.String(logging.UserKey, body.UserID))
+	job := func() { controller.processCommand(ctx, body) }
+	textTokens := strings.Fields(body.Text)
+	switch {
+	case len(textTokens) == 0 && body.TriggerID != "":
+		job = func() { controller.openChallengeView(ctx, body) }
+	case len(textTokens) > 0 && strings.EqualFold(textTokens[0], historyCommand):
+		job = func() { controller.processHistory(ctx, body, verified, textTokens[1:]) }
+	case len(textTokens) > 0 && strings.EqualFold(textTokens[0], cancelCommand):
+		job = func() { controller.processCancelCommand(ctx, body) }
+	}
+
+	if !controller.queue.Enqueue("command", job) {
+		fmt.Fprint(w, busyText)
+	}
+}
+
+// processCommand issues the challenge from a slash command.
+func (controller *controller) processCommand(ctx context.Context, body Body) {
+	command, err := commandFromBody(controller.clients, body)
+	if err == nil {
+		challenge, startErr := controller.StartChallenge(command)
+		if startErr != nil {
+			slog.ErrorContext(ctx, "Failed to issue the challenge", logging.SessionKey, challenge.SessionID, "error", startErr)
+			err = errors.New(chat.ErrorMessage(startErr, "There was a problem issuing the challenge. Please try again."))
+		}
+	}
+
+	if err != nil {
+		controller.followUp(ctx, body, err.Error())
+	}
+}
+
+// processCancelCommand withdraws the user's latest pending challenge in the channel.
+// The players' prompts are replaced and the target is notified by handleEvent.
+func (controller *controller) processCancelCommand(ctx context.Context, body Body) {
 	var (
-		target string
-		body   Body
+		id      string
+		command chat.Command
 	)
 
-	if r.Method == "POST" {
-		if debug {
-			controller.logRequest(r)
+	workspace := workspaceKey(body.EnterpriseID, body.TeamID)
+	for _, v := range controller.GameSessionsManager.Sessions() {
+		sessionCommand, ok := chat.SessionCommand(Platform, v)
+		if ok && v.Challenger == body.UserID && v.Result == "" && sessionCommand.Channel == body.ChannelID && sessionCommand.Workspace == workspace {
+			id, command = v.ID, sessionCommand
 		}
+	}
 
-		err := r.ParseForm()
-		if err != nil {
-			log.Print(err)
-			fmt.Fprint(w, "An error occurred while proccessing your response")
-			return
-		}
+	if id == "" {
+		controller.followUp(ctx, body, "You have no pending challenge to withdraw in this channel.")
+		return
+	}
 
-		err = decoder.Decode(&body, r.PostForm)
-		if err != nil {
-			log.Print(err)
-		}
-		textTokens := strings.Split(body.Text, " ")
+	if _, err := controller.GameSessionsManager.CancelSession(id, body.UserID); err != nil {
+		controller.followUp(ctx, body, cancelErrorText(err))
+		return
+	}
+	controller.followUp(ctx, body, withdrawnText(command))
+}
 
-		if len(textTokens) >= 1 {
-			target = textTokens[0]
-		}
+// followUp sends an ephemeral message to the user who issued a slash command.
+func (controller *controller) followUp(ctx context.Context, body Body, text string) {
+	if !validResponseURL(body.ResponseURL, controller.apiURL) {
+		slog.WarnContext(ctx, "Ignored a slash command with an invalid response_url", "response_url", body.ResponseURL)
+		return
+	}
 
-		controller.processChallengeAction(body.UserID, body.UserName, target, body.ChannelID, w)
+	client, err := controller.clients(workspaceKey(body.EnterpriseID, body.TeamID))
+	if err == nil {
+		err = client.PostResponse(body.ResponseURL, Message{Text: text})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to send the follow-up message", "error", err)
 	}
 }
 
@@ -72,240 +166,203 @@ func (controller *controller) HandleGamePayload(w http.ResponseWriter, r *http.R
 	)
 
 	if r.Method == "POST" {
-		if debug {
-			controller.logRequest(r)
+		if controller.signingSecret != "" && !controller.verifyRequest(r) {
+			http.Error(w, "The request could not be verified", http.StatusUnauthorized)
+			return
 		}
 
 		err := r.ParseForm()
 		if err != nil {
-			log.Print(err)
+			slog.WarnContext(r.Context(), "Failed to parse the payload", "error", err)
 			fmt.Fprint(w, "An error occurred while proccessing your response")
 			return
 		}
 
-		decoder.Decode(&payloadResponse, r.PostForm)
+		logRequest(r)
+		err = decoder.Decode(&payloadResponse, r.PostForm)
 		if err != nil {
-			log.Print(err)
+			slog.WarnContext(r.Context(), "Failed to decode the payload", "error", err)
 		}
 		json.Unmarshal([]byte(payloadResponse.Payload), &payload)
 
-		controller.processPayload(payload, w)
+		ctx := logging.With(r.Context(), slog.String(logging.TeamKey, payload.Team.ID), slog.String(logging.UserKey, payload.User.ID))
+		if payload.Type == viewSubmissionPayload {
+			controller.processViewSubmission(ctx, payload, w)
+			return
+		}
+		controller.processPayload(ctx, payload, w)
 	}
 
 }
 
-func (controller *controller) processChallengeAction(challenger, challengerName, target, channel string, w http.ResponseWriter) {
-	var (
-		slackAttachmentActions   []AttachmentAction
-		slackAttachmentActionMap = make(map[string]AttachmentAction)
-	)
-	target = strings.Split(strings.Replace(target, "<@", "", 1), "|")[0]
-	targetInfo, err := API.GetUserInfo(target)
-
-	if err != nil {
-		fmt.Fprint(w,
-			"The user you are attempting to challenge isn't a valid Slack user for this team.\n"+
-				"Make sure you are using the \"@\" mention syntax.",
-		)
+// processPayload locks in the move from an interactive message, replacing the message's buttons with the move played.
+// Once both players have locked in, the result replaces both players' messages and is posted to the channel by the chat.Service.
+// The challenger's Cancel button withdraws the challenge instead.
+func (controller *controller) processPayload(ctx context.Context, payload Payload, w http.ResponseWriter) {
+	if len(payload.Actions) == 0 {
+		controller.respond(ctx, payload, w, "No action was submitted")
 		return
 	}
-	targetName := targetInfo.Name
-	form := url.Values{}
-
-	slackData := createSlackData(channel, challengerName, targetName)
-	uuid := controller.GameSessionsManager.CreateSession(challenger, target, slackData)
-	gameMoves := controller.Game.Moves
-	preferredMoveOrder := controller.Game.PreferredOrder
 
-	for _, v := range gameMoves {
-		jsonData, err := json.Marshal(map[string]string{
-			"session_id": uuid,
-			"move":       strings.ToLower(v.Name),
-		})
-
-		if err == nil {
-			slackAttachmentActionMap[v.Name] = AttachmentAction{
-				Name:  "move",
-				Text:  strings.Title(v.Name),
-				Type:  "button",
-				Value: string(jsonData),
-			}
-		} else {
-			log.Print(err)
-			fmt.Fprint(w, "An error occurred while building the moves for the game")
-			return
-		}
-	}
-
-	for _, move := range preferredMoveOrder {
-		slackAttachmentActions = append(slackAttachmentActions, slackAttachmentActionMap[move])
-	}
-
-	respAttachment := []Attachment{
-		Attachment{
-			Text:           "Please select your move",
-			Fallback:       "You are unable to choose a move",
-			CallbackID:     "player_move_selection",
-			Color:          "#3AA3E3",
-			AttachmentType: "default",
-			Actions:        slackAttachmentActions,
-		},
-	}
-	js, err := json.Marshal(respAttachment)
+	var payloadValue payloadValue
+	user := payload.User.ID
 
+	err := json.Unmarshal([]byte(payload.Actions[0].Value), &payloadValue)
 	if err != nil {
-		log.Print(err)
-		fmt.Fprint(w, "An error occurred while setting up the game.")
+		controller.respond(ctx, payload, w, "There was a problem processing the game move payload.")
 		return
 	}
+	ctx = logging.With(ctx, slog.String(logging.SessionKey, payloadValue.SessionID))
 
-	resp := PostEphemeralPayload{
-		Token:       OAuthToken,
-		Channel:     channel,
-		Text:        fmt.Sprintf("@%v has challenged you to a game of RPS.", challengerName),
-		User:        target,
-		AsUser:      false,
-		Attachments: string(js),
+	v, ok := controller.GameSessionsManager.Session(payloadValue.SessionID)
+	command, isSlackSession := chat.SessionCommand(Platform, v)
+	if ok && !isSlackSession {
+		controller.respond(ctx, payload, w, "Game session does not support Slack.")
+		return
 	}
 
-	err = encoder.Encode(resp, form)
-	if err != nil {
-		fmt.Fprint(w, "There was a problem building the challenge for your opponent. Please try again.")
+	if action := payload.Actions[0]; action.ActionID == cancelActionID || action.Name == cancelActionID {
+		if _, err := controller.GameSessionsManager.CancelSession(payloadValue.SessionID, user); err != nil {
+			controller.respond(ctx, payload, w, cancelErrorText(err))
+			return
+		}
+		controller.respond(ctx, payload, w, withdrawnText(command))
 		return
 	}
 
-	_, err = http.PostForm(PostEphemeralRoute, form)
-	if err != nil {
-		fmt.Fprint(w, "There was a problem issuing the challenge to your opponent. Please try again.")
-		return
+	// The response_url is kept until the result is known, so the message can be replaced for both players.
+	if ok && v.Result == "" && (user == v.Challenger || user == v.Target) && validResponseURL(payload.ResponseURL, controller.apiURL) {
+		controller.GameSessionsManager.SetData(v.ID, responseURLKeyPrefix+user, payload.ResponseURL)
 	}
 
-	resp = PostEphemeralPayload{
-		Token:       OAuthToken,
-		Channel:     channel,
-		Text:        fmt.Sprintf("The challenge was submitted to @%v. They are now selecting a move.", targetName),
-		User:        challenger,
-		AsUser:      false,
-		Attachments: string(js),
+	v, err = controller.SubmitMove(payloadValue.SessionID, user, payloadValue.Move)
+	switch err {
+	case nil:
+		if v.Result == "" {
+			opponent := command.Target
+			if user == v.Target {
+				opponent = command.Challenger
+			}
+			controller.respond(ctx, payload, w, fmt.Sprintf("You played %v — waiting for @%v", payloadValue.Move, opponent.Name))
+		}
+	case server.ErrSessionNotFound:
+		controller.respond(ctx, payload, w, "An invalid session id was passed with your move. Maybe the game session has expired.")
+	case server.ErrNotSessionPlayer:
+		controller.respond(ctx, payload, w, "A user not associated to the sessions attempted to submit a game move.")
+	case server.ErrMoveLocked:
+		controller.respond(ctx, payload, w, "You have already locked in your move.")
+	case server.ErrSessionComplete:
+		controller.respond(ctx, payload, w, "This game has already been played.")
+	default:
+		controller.respond(ctx, payload, w, err.Error())
 	}
+}
 
-	err = encoder.Encode(resp, form)
-	if err != nil {
-		fmt.Fprint(w, "There was a problem building the challenge for you. Please try again.")
+// respond replies to an interaction with a message replacing the one the action was taken on.
+// Interactive messages use the response body, while Slack ignores it for Block Kit actions,
+// which are replied to using the response_url from the work queue.
+func (controller *controller) respond(ctx context.Context, payload Payload, w http.ResponseWriter, text string) {
+	if payload.Type != blockActionsPayload || payload.ResponseURL == "" {
+		fmt.Fprint(w, text)
 		return
 	}
-
-	_, err = http.PostForm(PostEphemeralRoute, form)
-	if err != nil {
-		fmt.Fprint(w, "There was a problem returning the game back to you. Please try again.")
+	if !validResponseURL(payload.ResponseURL, controller.apiURL) {
+		slog.WarnContext(ctx, "Ignored an interaction with an invalid response_url", "response_url", payload.ResponseURL)
 		return
 	}
+
+	ctx = logging.Detach(ctx)
+	controller.queue.Enqueue("response", func() {
+		client, err := controller.clients(workspaceKey(payload.Team.EnterpriseID, payload.Team.ID))
+		if err == nil {
+			err = client.UpdateMessage(payload.ResponseURL, Message{Text: text})
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to replace the message", "error", err)
+		}
+	})
 }
 
-func (controller *controller) processPayload(payload Payload, w http.ResponseWriter) {
-	if len(payload.Actions) == 0 {
-		fmt.Fprint(w, "No action was submitted")
+// handleEvent replaces the prompts of a withdrawn Slack challenge and notifies its target, on the work queue as listeners run under the session lock.
+// Sessions cancelled without a player, by an admin or because their prompts couldn't be sent, are left alone.
+func (controller *controller) handleEvent(event server.Event) {
+	if event.Type != server.EventCancelled || event.Player == "" {
 		return
 	}
 
-	var (
-		payloadValue payloadValue
-		playResult   string
-	)
-	gameSessions := controller.GameSessionsManager.GameSessions
-	user := payload.User.ID
+	if command, ok := chat.SessionCommand(Platform, event.Session); ok {
+		controller.queue.Enqueue("withdrawn", func() { controller.replaceWithdrawnChallenge(event.Session, command) })
+	}
+}
 
-	err := json.Unmarshal([]byte(payload.Actions[0].Value), &payloadValue)
+// replaceWithdrawnChallenge replaces the players' prompts with a withdrawn message and notifies the target in the channel.
+// Prompts are ephemeral, so only the ones a player has already used, and whose response_url was stored, can be replaced.
+// The buttons of the others report the session as expired.
+func (controller *controller) replaceWithdrawnChallenge(session server.GameSession, command chat.Command) {
+	client, err := controller.clients(command.Workspace)
 	if err != nil {
-		fmt.Fprint(w, "There was a problem processing the game move payload.")
+		slog.Error("Failed to replace the withdrawn challenge", logging.SessionKey, session.ID, "error", err)
+		return
 	}
 
-	if v, ok := gameSessions[payloadValue.SessionID]; ok {
-		if user == v.Challenger {
-			v.ChallengerMove = payloadValue.Move
-			gameSessions[payloadValue.SessionID] = v
-			fmt.Fprint(w, "Your move has been locked in")
-		} else if user == v.Target {
-			v.TargetMove = payloadValue.Move
-			gameSessions[payloadValue.SessionID] = v
-			fmt.Fprint(w, "Your move has been locked in")
-		} else {
-			fmt.Fprint(w, "A user not associated to the sessions attempted to submit a game move.")
-			return
+	message := Message{Text: withdrawnText(command)}
+	for _, player := range []string{session.Challenger, session.Target} {
+		if responseURL, ok := session.Data[responseURLKeyPrefix+player]; ok && validResponseURL(responseURL, controller.apiURL) {
+			if err := client.UpdateMessage(responseURL, message); err != nil {
+				slog.Error("Failed to replace the player's message", logging.SessionKey, session.ID, logging.UserKey, player, "error", err)
+			}
 		}
+	}
 
-		if len(v.ChallengerMove) != 0 && len(v.TargetMove) != 0 {
-			result, err := controller.Game.Play(v.ChallengerMove, v.TargetMove)
+	if err := client.PostEphemeral(command.Channel, session.Target, message); err != nil {
+		slog.Error("Failed to notify the target of the withdrawn challenge", logging.SessionKey, session.ID, "error", err)
+	}
+}
 
-			if err != nil {
-				fmt.Fprint(w, err)
-				return
-			}
+// verifyRequest reports whether the request was signed by Slack, restoring the body so the form can be parsed afterwards.
+// Without a signing secret, no request can be verified.
+func (controller *controller) verifyRequest(r *http.Request) bool {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxEventSize))
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-			if !validSlackData(v.Data) {
-				fmt.Fprint(w, "Game session does not support Slack.")
-				return
-			}
-			channelName := v.Data["channelName"]
-			challengerName := v.Data["challengerName"]
-			targetName := v.Data["targetName"]
-
-			if result == game.GameStatePlayerOneWins {
-				playResult = fmt.Sprintf(
-					"@%v defeated @%v, %v beats %v",
-					challengerName,
-					targetName,
-					v.ChallengerMove,
-					v.TargetMove,
-				)
-			} else if result == game.GameStatePlayerTwoWins {
-				playResult = fmt.Sprintf(
-					"@%v defeated @%v, %v beats %v",
-					targetName,
-					challengerName,
-					v.TargetMove,
-					v.ChallengerMove,
-				)
-			} else {
-				playResult = fmt.Sprintf(
-					"@%v and @%v had a draw. Both played %v",
-					challengerName,
-					targetName,
-					v.TargetMove,
-				)
-			}
+	return err == nil && validSignature(controller.signingSecret, r.Header, body, time.Now())
+}
 
-			_, _, err = API.PostMessage(channelName, playResult, slack.PostMessageParameters{})
-			if err != nil {
-				log.Print(err)
-				fmt.Fprint(w, "Failed to post the game results to the channel.")
-			}
-		}
-	} else {
-		fmt.Fprint(w, "An invalid session id was passed with your move. Maybe the game session has expired.")
-		return
+func cancelErrorText(err error) string {
+	switch err {
+	case server.ErrSessionNotFound:
+		return "The challenge has already expired."
+	case server.ErrSessionComplete:
+		return "This game has already been played."
+	default:
+		return err.Error()
 	}
 }
 
-func (controller *controller) logRequest(r *http.Request) {
-	requestDump, err := httputil.DumpRequest(r, true)
-	if err != nil {
-		log.Print(err)
-	}
-	log.Print(string(requestDump))
+func withdrawnText(command chat.Command) string {
+	return fmt.Sprintf("Challenge withdrawn: @%v withdrew their challenge to @%v.", command.Challenger.Name, command.Target.Name)
 }
 
-func newController(gameServer *server.GameServer) *controller {
-	return &controller{
-		GameServer: DefaultGameServer,
-	}
+// logRequest logs the form sent by Slack at the debug level, the form's secrets are redacted by the logger.
+func logRequest(r *http.Request) {
+	slog.DebugContext(r.Context(), "Received a Slack request", "path", r.URL.Path, "form", logging.Values(r.PostForm))
 }
 
-func init() {
-	debug = os.Getenv("RPS_DEBUG") == "1"
-	commandName = os.Getenv("RPS_COMMAND_NAME")
+func newController(gameServer *server.GameServer, clients ClientFactory, cfg config.Config, history audit.Reader) *controller {
+	adapter := &adapter{
+		clients:           clients,
+		apiURL:            cfg.Slack.BaseURL,
+		legacyAttachments: cfg.Slack.LegacyAttachments,
+	}
 
-	if commandName == "" {
-		commandName = defaultAcceptCommandName
+	return &controller{
+		Service:       chat.NewService(gameServer, adapter),
+		clients:       clients,
+		queue:         newWorkQueue(defaultQueueWorkers, defaultQueueSize),
+		signingSecret: cfg.Slack.SigningSecret,
+		apiURL:        cfg.Slack.BaseURL,
+		history:       history,
+		admins:        cfg.Slack.Admins,
+		defaultMode:   cfg.Game,
 	}
 }
@@ -0,0 +1,446 @@
+package slack
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/config"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+// slackCall is a request received by the fakeSlack server.
+// Method is the Web API method, or the path of the response_url for message updates.
+type slackCall struct {
+	Method string
+	Form   url.Values
+	Body   []byte
+}
+
+// fakeSlack is a Slack Web API server recording every call it receives.
+type fakeSlack struct {
+	*httptest.Server
+	calls  chan slackCall
+	users  map[string]string
+	errors map[string]string // Error codes returned by Web API methods instead of responding ok.
+}
+
+func newFakeSlack() *fakeSlack {
+	fake := &fakeSlack{
+		calls:  make(chan slackCall, 32),
+		users:  map[string]string{"U1": "alice", "U2": "bob"},
+		errors: map[string]string{},
+	}
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		call := slackCall{Method: strings.TrimPrefix(r.URL.Path, "/api/"), Form: form, Body: body}
+
+		if call.Method == UsersInfoMethod {
+			if name, ok := fake.users[form.Get("user")]; ok {
+				json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "user": User{ID: form.Get("user"), Name: name}})
+			} else {
+				json.NewEncoder(w).Encode(APIResponse{Error: "user_not_found"})
+			}
+			return
+		}
+
+		fake.calls <- call
+		code, failed := fake.errors[call.Method]
+		json.NewEncoder(w).Encode(APIResponse{OK: !failed, Error: code})
+	}))
+
+	return fake
+}
+
+// receive returns the next n calls made to the fake server.
+func (fake *fakeSlack) receive(t *testing.T, n int) []slackCall {
+	var calls []slackCall
+
+	for len(calls) < n {
+		select {
+		case call := <-fake.calls:
+			calls = append(calls, call)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %v calls to Slack, got %+v", n, calls)
+		}
+	}
+
+	return calls
+}
+
+func createMockSlackServer() (*server.GameServer, *fakeSlack) {
+	fake := newFakeSlack()
+	cfg := config.Default()
+	cfg.Slack.BaseURL = fake.URL + "/api"
+
+	gameServer := server.NewGameServer(modes.StandardGame)
+	registerRoutes(gameServer, cfg, NewMemoryTokenStore(), NewClientFactory(cfg.Slack.BaseURL, NewMemoryTokenStore(), "xoxb-test"), nil)
+
+	return gameServer, fake
+}
+
+func postForm(gameServer *server.GameServer, route string, form url.Values) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", route, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, r)
+
+	return w
+}
+
+func slashCommand(responseURL, text string) url.Values {
+	return url.Values{
+		"team_id":      {"T1"},
+		"user_id":      {"U1"},
+		"user_name":    {"alice"},
+		"channel_id":   {"C1"},
+		"text":         {text},
+		"trigger_id":   {"trigger"},
+		"response_url": {responseURL},
+	}
+}
+
+func blockAction(responseURL, user, actionID, value string) url.Values {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":         blockActionsPayload,
+		"team":         Team{ID: "T1"},
+		"user":         User{ID: user},
+		"response_url": responseURL,
+		"actions":      []map[string]string{{"action_id": actionID, "block_id": moveBlockID, "value": value}},
+	})
+
+	return url.Values{"payload": {string(payload)}}
+}
+
+// promptMove returns the value of the prompt's button for the move.
+func promptMove(t *testing.T, prompt slackCall, move string) string {
+	value, ok := promptButton(prompt, moveActionPrefix+move)
+	if !ok {
+		t.Fatalf("Expected the prompt to have a %v button, got %v", move, prompt.Form.Get("blocks"))
+	}
+
+	return value
+}
+
+// promptButton returns the value of the prompt's button with the action id.
+func promptButton(prompt slackCall, actionID string) (string, bool) {
+	var blocks []struct {
+		Elements []ButtonElement `json:"elements"`
+	}
+
+	json.Unmarshal([]byte(prompt.Form.Get("blocks")), &blocks)
+	for _, block := range blocks {
+		for _, button := range block.Elements {
+			if button.ActionID == actionID {
+				return button.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func TestSlashCommandPlaysAGame(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+
+	w := postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "<@U2|bob>"))
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Fatalf("Expected an empty response, got %v: %q", w.Code, w.Body.String())
+	}
+
+	prompts := fake.receive(t, 2)
+	if prompts[0].Method != PostEphemeralMethod || prompts[0].Form.Get("user") != "U2" || prompts[1].Form.Get("user") != "U1" {
+		t.Fatalf("Expected both players to be prompted, got %+v", prompts)
+	}
+	if prompts[0].Form.Get("token") != "xoxb-test" || prompts[0].Form.Get("channel") != "C1" {
+		t.Fatalf("Expected the prompt to be posted in the channel with the default token, got %v", prompts[0].Form)
+	}
+
+	postForm(gameServer, HandleGamePayloadRoute, blockAction(fake.URL+"/response/U1", "U1", moveActionPrefix+"rock", promptMove(t, prompts[1], "rock")))
+	if waiting := fake.receive(t, 1)[0]; waiting.Method != "/response/U1" || !strings.Contains(string(waiting.Body), "You played rock — waiting for @bob") {
+		t.Fatalf("Expected the challenger's prompt to be replaced, got %+v", waiting)
+	}
+
+	postForm(gameServer, HandleGamePayloadRoute, blockAction(fake.URL+"/response/U2", "U2", moveActionPrefix+"scissors", promptMove(t, prompts[0], "scissors")))
+
+	calls := map[string]slackCall{}
+	for _, call := range fake.receive(t, 3) {
+		calls[call.Method] = call
+	}
+
+	result := "@alice defeated @bob, rock beats scissors"
+	if !strings.Contains(string(calls["/response/U1"].Body), result) || !strings.Contains(string(calls["/response/U2"].Body), result) {
+		t.Fatalf("Expected both prompts to be replaced with the result, got %+v", calls)
+	}
+	if message := calls[PostMessageMethod]; message.Form.Get("channel") != "C1" || message.Form.Get("text") != result {
+		t.Fatalf("Expected the result to be posted to the channel, got %+v", message)
+	}
+}
+
+func TestCancelCommandWithdrawsChallenge(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "<@U2|bob>"))
+	prompts := fake.receive(t, 2)
+	if _, ok := promptButton(prompts[0], cancelActionID); ok {
+		t.Fatal("Expected only the challenger's prompt to have a Cancel button")
+	}
+	cancel, ok := promptButton(prompts[1], cancelActionID)
+	if !ok {
+		t.Fatalf("Expected the challenger's prompt to have a Cancel button, got %v", prompts[1].Form.Get("blocks"))
+	}
+
+	postForm(gameServer, HandleGamePayloadRoute, blockAction(fake.URL+"/response/U2", "U2", cancelActionID, cancel))
+	if response := fake.receive(t, 1)[0]; !strings.Contains(string(response.Body), "Only the challenger can withdraw the challenge") {
+		t.Fatalf("Expected the target to be refused, got %+v", response)
+	}
+
+	postForm(gameServer, HandleGamePayloadRoute, blockAction(fake.URL+"/response/U2", "U2", moveActionPrefix+"rock", promptMove(t, prompts[0], "rock")))
+	fake.receive(t, 1)
+
+	command := slashCommand(fake.URL+"/response/command", "cancel")
+	command.Set("channel_id", "C2")
+	postForm(gameServer, HandleGameRequestRoute, command)
+	if response := fake.receive(t, 1)[0]; !strings.Contains(string(response.Body), "You have no pending challenge to withdraw in this channel.") {
+		t.Fatalf("Expected challenges in other channels to be kept, got %+v", response)
+	}
+
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "cancel"))
+	calls := map[string]slackCall{}
+	for _, call := range fake.receive(t, 3) {
+		calls[call.Method] = call
+	}
+
+	withdrawn := "Challenge withdrawn: @alice withdrew their challenge to @bob."
+	if !strings.Contains(string(calls["/response/command"].Body), withdrawn) || !strings.Contains(string(calls["/response/U2"].Body), withdrawn) {
+		t.Fatalf("Expected the challenger and the target's prompt to be told, got %+v", calls)
+	}
+	if notification := calls[PostEphemeralMethod]; notification.Form.Get("user") != "U2" || notification.Form.Get("text") != withdrawn {
+		t.Fatalf("Expected the target to be notified, got %+v", notification)
+	}
+	if len(gameServer.GameSessionsManager.Sessions()) != 0 {
+		t.Fatal("Expected the session to be removed")
+	}
+}
+
+func TestCancelButtonWithdrawsChallenge(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "<@U2|bob>"))
+	cancel, _ := promptButton(fake.receive(t, 2)[1], cancelActionID)
+
+	postForm(gameServer, HandleGamePayloadRoute, blockAction(fake.URL+"/response/U1", "U1", cancelActionID, cancel))
+	calls := map[string]slackCall{}
+	for _, call := range fake.receive(t, 2) {
+		calls[call.Method] = call
+	}
+
+	withdrawn := "Challenge withdrawn: @alice withdrew their challenge to @bob."
+	if !strings.Contains(string(calls["/response/U1"].Body), withdrawn) || calls[PostEphemeralMethod].Form.Get("user") != "U2" {
+		t.Fatalf("Expected the challenger's prompt to be replaced and the target to be notified, got %+v", calls)
+	}
+}
+
+func TestSlashCommandRejectsUnknownUsers(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "<@U9|eve>"))
+	if response := fake.receive(t, 1)[0]; !strings.Contains(string(response.Body), "isn't a valid Slack user") {
+		t.Fatalf("Expected the user to be rejected through the response_url, got %+v", response)
+	}
+
+	if len(gameServer.GameSessionsManager.Sessions()) != 0 {
+		t.Fatal("Expected no session to be created")
+	}
+}
+
+func TestSlashCommandRejectsSelfChallenges(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "<@U1|alice>"))
+	if response := fake.receive(t, 1)[0]; !strings.Contains(string(response.Body), "You need to challenge another person.") {
+		t.Fatalf("Expected the self challenge to be rejected through the response_url, got %+v", response)
+	}
+
+	if len(gameServer.GameSessionsManager.Sessions()) != 0 {
+		t.Fatal("Expected no session to be created")
+	}
+}
+
+func TestSlashCommandShowsSlackErrors(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+	fake.errors[PostEphemeralMethod] = "not_in_channel"
+
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "<@U2|bob>"))
+
+	var response Response
+	calls := fake.receive(t, 2)
+	json.Unmarshal(calls[1].Body, &response)
+
+	if calls[1].Method != "/response/command" || response.Text != "RPS can't post in this channel. Please invite the app to the channel and try again." {
+		t.Fatalf("Expected the Slack error to be explained through the response_url, got %+v", calls[1])
+	}
+}
+
+func TestSlashCommandWithoutTextOpensChallengeView(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", ""))
+
+	var payload ViewsOpenPayload
+	call := fake.receive(t, 1)[0]
+	json.Unmarshal(call.Body, &payload)
+
+	if call.Method != ViewsOpenMethod || payload.TriggerID != "trigger" || payload.View.PrivateMetadata != "C1" {
+		t.Fatalf("Expected the challenge view to be opened, got %+v", call)
+	}
+}
+
+func TestViewSubmissionStartsChallenge(t *testing.T) {
+	gameServer, fake := createMockSlackServer()
+	defer fake.Close()
+
+	submission := viewSubmission("U1", "U2", "rpsls", "3")
+	submission.Team = Team{ID: "T1"}
+	payload, _ := json.Marshal(submission)
+
+	w := postForm(gameServer, HandleGamePayloadRoute, url.Values{"payload": {string(payload)}})
+	if w.Body.Len() != 0 {
+		t.Fatalf("Expected the modal to be closed, got %q", w.Body.String())
+	}
+
+	prompts := fake.receive(t, 2)
+	promptMove(t, prompts[0], "spock")
+
+	sessions := gameServer.GameSessionsManager.Sessions()
+	if len(sessions) != 1 || sessions[0].Mode != "rpsls" || sessions[0].Data["bestOf"] != "3" {
+		t.Fatalf("Expected a best of 3 rpsls session, got %+v", sessions)
+	}
+}
+
+func TestReadinessVerifiesDefaultToken(t *testing.T) {
+	fake := newFakeSlack()
+	defer fake.Close()
+
+	cfg := config.Default()
+	cfg.Slack.OAuthToken = "xoxb-test"
+	cfg.Slack.BaseURL = fake.URL + "/api"
+
+	gameServer := server.NewGameServer(modes.StandardGame)
+	gameServer.RegisterHealthRoutes()
+	registerRoutes(gameServer, cfg, NewMemoryTokenStore(), NewClientFactory(cfg.Slack.BaseURL, NewMemoryTokenStore(), cfg.Slack.OAuthToken), nil)
+	fake.errors[AuthTestMethod] = "invalid_auth"
+
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, httptest.NewRequest("GET", server.ReadyRoute, nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "invalid_auth") {
+		t.Fatalf("Expected an invalid token to fail readiness, got %v %q", w.Code, w.Body.String())
+	}
+
+	delete(fake.errors, AuthTestMethod)
+
+	w = httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, httptest.NewRequest("GET", server.ReadyRoute, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected a valid token to pass readiness, got %v %q", w.Code, w.Body.String())
+	}
+
+	if call := fake.receive(t, 2)[1]; call.Method != AuthTestMethod || call.Form.Get("token") != "xoxb-test" {
+		t.Fatalf("Expected the token to be verified through auth.test, got %+v", call)
+	}
+}
+
+type fakeHistory map[string][]audit.Entry
+
+func (history fakeHistory) History(session string) ([]audit.Entry, error) {
+	return history[session], nil
+}
+
+func TestHistoryCommandIsLimitedToAdmins(t *testing.T) {
+	gameServer, fake := createMockHistoryServer(signingSecret)
+	defer fake.Close()
+
+	var response Response
+	postSignedForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "history abc"))
+	json.Unmarshal(fake.receive(t, 1)[0].Body, &response)
+	if !strings.Contains(response.Text, "<@U1> challenged <@U2> (standard)") || !strings.Contains(response.Text, "<@U2> tried to play rock: The session is complete") {
+		t.Fatalf("Expected the admin to get the history, got %q", response.Text)
+	}
+
+	command := slashCommand(fake.URL+"/response/command", "history abc")
+	command.Set("user_id", "U2")
+	postSignedForm(gameServer, HandleGameRequestRoute, command)
+	json.Unmarshal(fake.receive(t, 1)[0].Body, &response)
+	if response.Text != "Only admins can look up the history of a session." {
+		t.Fatalf("Expected other users to be refused, got %q", response.Text)
+	}
+}
+
+func TestRejectsForgedRequests(t *testing.T) {
+	gameServer, fake := createMockHistoryServer(signingSecret)
+	defer fake.Close()
+
+	for _, route := range []string{HandleGameRequestRoute, HandleGamePayloadRoute} {
+		w := postForm(gameServer, route, slashCommand(fake.URL+"/response/command", "history abc"))
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected an unsigned request to %v to be rejected, got %v", route, w.Code)
+		}
+	}
+}
+
+func TestHistoryCommandRequiresSigningSecret(t *testing.T) {
+	gameServer, fake := createMockHistoryServer("")
+	defer fake.Close()
+
+	var response Response
+	postForm(gameServer, HandleGameRequestRoute, slashCommand(fake.URL+"/response/command", "history abc"))
+	json.Unmarshal(fake.receive(t, 1)[0].Body, &response)
+	if !strings.Contains(response.Text, "signing secret") {
+		t.Fatalf("Expected the history to be refused without a signing secret, got %q", response.Text)
+	}
+}
+
+// createMockHistoryServer registers the Slack routes with U1 as an admin, looking up the history of the "abc" session.
+func createMockHistoryServer(secret string) (*server.GameServer, *fakeSlack) {
+	fake := newFakeSlack()
+	history := fakeHistory{"abc": {
+		{Type: audit.EntryCreated, Session: "abc", Challenger: "U1", Target: "U2"},
+		{Type: audit.EntryMoveRejected, Session: "abc", Actor: "U2", Move: "rock", Error: "The session is complete"},
+	}}
+
+	cfg := config.Default()
+	cfg.Slack.Admins = []string{"U1"}
+	cfg.Slack.BaseURL = fake.URL + "/api"
+	cfg.Slack.SigningSecret = secret
+	gameServer := server.NewGameServer(modes.StandardGame)
+	registerRoutes(gameServer, cfg, NewMemoryTokenStore(), NewClientFactory(cfg.Slack.BaseURL, NewMemoryTokenStore(), "xoxb-test"), history)
+
+	return gameServer, fake
+}
+
+func postSignedForm(gameServer *server.GameServer, route string, form url.Values) *httptest.ResponseRecorder {
+	body := form.Encode()
+	r := signedEventRequest(signingSecret, time.Now(), body)
+	r.URL.Path = route
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, r)
+
+	return w
+}
@@ -0,0 +1,248 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/logging"
+	"github.com/hamologist/rps/server"
+)
+
+const (
+	urlVerificationEvent = "url_verification"
+	eventCallbackEvent   = "event_callback"
+	appMentionEvent      = "app_mention"
+	appHomeOpenedEvent   = "app_home_opened"
+	homeTab              = "home"
+
+	signatureHeader = "X-Slack-Signature"
+	timestampHeader = "X-Slack-Request-Timestamp"
+	retryHeader     = "X-Slack-Retry-Num"
+	signatureMaxAge = 5 * time.Minute
+
+	// maxEventSize caps the size of the events, commands and payloads read from Slack.
+	maxEventSize = 1 << 20
+	// recentResultsShown is the number of results listed in the App Home tab.
+	recentResultsShown = 5
+)
+
+const mentionUsage = "To start a game, mention me with `challenge @someone`, optionally followed by a game mode."
+
+// HandleEvents handles the requests sent by the Events API.
+// Events are acknowledged straight away and processed on the work queue, as Slack expects a response within three seconds.
+func (controller *controller) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	var callback EventCallback
+
+	if r.Method != "POST" {
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxEventSize))
+	if err != nil || !validSignature(controller.signingSecret, r.Header, body, time.Now()) {
+		http.Error(w, "The request could not be verified", http.StatusUnauthorized)
+		return
+	}
+
+	if err := json.Unmarshal(body, &callback); err != nil {
+		http.Error(w, "The request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+
+	switch callback.Type {
+	case urlVerificationEvent:
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, callback.Challenge)
+	case eventCallbackEvent:
+		// Retries are sent when an event wasn't acknowledged in time, the original has already been processed.
+		if r.Header.Get(retryHeader) == "" {
+			ctx := logging.With(logging.Detach(r.Context()),
+				slog.String(logging.TeamKey, callback.TeamID),
+				slog.String(logging.UserKey, callback.Event.User),
+			)
+			controller.queue.Enqueue("event", func() { controller.processEvent(ctx, callback) })
+		}
+	}
+}
+
+func (controller *controller) processEvent(ctx context.Context, callback EventCallback) {
+	event := callback.Event
+	workspace := workspaceKey(callback.EnterpriseID, callback.TeamID)
+
+	switch event.Type {
+	case appMentionEvent:
+		controller.processMention(ctx, workspace, event)
+	case appHomeOpenedEvent:
+		if event.Tab == homeTab {
+			controller.publishHome(ctx, workspace, event.User)
+		}
+	}
+}
+
+// processMention starts a game when the app is mentioned with "challenge @someone".
+func (controller *controller) processMention(ctx context.Context, workspace string, event Event) {
+	client, err := controller.clients(workspace)
+	if err != nil {
+		slog.WarnContext(ctx, "Ignored a mention", "error", err)
+		return
+	}
+
+	reply := func(text string) {
+		if err := client.PostEphemeral(event.Channel, event.User, Message{Text: text}); err != nil {
+			slog.ErrorContext(ctx, "Failed to reply to the mention", "error", err)
+		}
+	}
+
+	// The text starts with the mention of the app itself.
+	textTokens := strings.Fields(event.Text)
+	if len(textTokens) > 0 && strings.HasPrefix(textTokens[0], "<@") {
+		textTokens = textTokens[1:]
+	}
+
+	if len(textTokens) < 2 || !strings.EqualFold(textTokens[0], "challenge") {
+		reply(mentionUsage)
+		return
+	}
+
+	target, mode, err := parseChallenge(client, textTokens[1:])
+	if err != nil {
+		reply(err.Error())
+		return
+	}
+	if target.ID == event.User {
+		reply("You need to challenge another person.")
+		return
+	}
+
+	challenger := chat.Player{ID: event.User, Name: event.User}
+	if challengerInfo, err := client.GetUserInfo(event.User); err == nil {
+		challenger.Name = challengerInfo.Name
+	}
+
+	challenge, err := controller.StartChallenge(chat.Command{
+		Challenger: challenger,
+		Target:     target,
+		Channel:    event.Channel,
+		Workspace:  workspace,
+		Mode:       mode,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to issue the challenge", logging.SessionKey, challenge.SessionID, "error", err)
+		reply(chat.ErrorMessage(err, "There was a problem issuing the challenge. Please try again."))
+	}
+}
+
+// publishHome publishes the user's App Home tab.
+func (controller *controller) publishHome(ctx context.Context, workspace, user string) {
+	client, err := controller.clients(workspace)
+	if err != nil {
+		slog.WarnContext(ctx, "Ignored the App Home", "error", err)
+		return
+	}
+
+	err = client.PublishView(user, homeView(workspace, user, controller.GameSessionsManager.Sessions()))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to publish the App Home", "error", err)
+	}
+}
+
+// homeView renders the user's stats, pending challenges and recent results from the sessions of the workspace.
+// Sessions are only kept in memory until they expire, so the stats are labelled as covering recent games only.
+func homeView(workspace, user string, sessions []server.GameSession) *View {
+	var (
+		wins, losses, draws int
+		pending, results    []string
+	)
+
+	mention := func(player chat.Player) string {
+		return "@" + player.Name
+	}
+
+	for _, v := range sessions {
+		command, ok := chat.SessionCommand(Platform, v)
+		if !ok || command.Workspace != workspace || (v.Challenger != user && v.Target != user) {
+			continue
+		}
+
+		isChallenger := v.Challenger == user
+		opponent, move := command.Target, v.ChallengerMove
+		if !isChallenger {
+			opponent, move = command.Challenger, v.TargetMove
+		}
+
+		switch {
+		case v.Result == "":
+			status := "waiting for your move"
+			if move != "" {
+				status = fmt.Sprintf("waiting for %v", mention(opponent))
+			}
+			pending = append(pending, fmt.Sprintf("• vs %v: %v", mention(opponent), status))
+			continue
+		case v.Result == game.GameStateDraw:
+			draws++
+		case (v.Result == game.GameStatePlayerOneWins) == isChallenger:
+			wins++
+		default:
+			losses++
+		}
+
+		results = append([]string{"• " + strings.Replace(chat.FormatResult(v, mention), "\n", " ", -1)}, results...)
+	}
+
+	if len(results) > recentResultsShown {
+		results = results[:recentResultsShown]
+	}
+
+	return &View{
+		Type: ViewTypeHome,
+		Blocks: []Block{
+			NewSectionBlock(fmt.Sprintf(
+				"*Your recent stats*\nWins: %v   Losses: %v   Draws: %v", wins, losses, draws,
+			), nil),
+			NewSectionBlock("*Pending challenges*\n"+listOrNone(pending), nil),
+			NewSectionBlock("*Recent results*\n"+listOrNone(results), nil),
+			NewContextBlock(mentionUsage, "Stats and results only cover the games issued in the last 30 minutes."),
+		},
+	}
+}
+
+func listOrNone(lines []string) string {
+	if len(lines) == 0 {
+		return "None"
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// validSignature verifies a request was signed by Slack using the app's signing secret.
+// Requests older than signatureMaxAge are rejected, protecting against replays.
+func validSignature(secret string, header http.Header, body []byte, now time.Time) bool {
+	timestamp := header.Get(timestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || secret == "" {
+		return false
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > signatureMaxAge || age < -signatureMaxAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+
+	return hmac.Equal([]byte(header.Get(signatureHeader)), []byte("v0="+hex.EncodeToString(mac.Sum(nil))))
+}
@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+const signingSecret = "signing secret"
+
+func TestEventsURLVerification(t *testing.T) {
+	controller := &controller{Service: &chat.Service{GameServer: server.NewGameServer(modes.StandardGame)}, signingSecret: signingSecret}
+	body := `{"type": "url_verification", "challenge": "abc123"}`
+
+	w := httptest.NewRecorder()
+	controller.HandleEvents(w, signedEventRequest(signingSecret, time.Now(), body))
+	if w.Code != http.StatusOK || w.Body.String() != "abc123" {
+		t.Fatalf("Expected the challenge to be echoed, got %v %q", w.Code, w.Body.String())
+	}
+
+	for _, r := range []*http.Request{
+		signedEventRequest("other secret", time.Now(), body),
+		signedEventRequest(signingSecret, time.Now().Add(-signatureMaxAge-time.Minute), body),
+	} {
+		w := httptest.NewRecorder()
+		controller.HandleEvents(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status %v, got %v", http.StatusUnauthorized, w.Code)
+		}
+	}
+}
+
+func TestHomeView(t *testing.T) {
+	data := chat.CreateSessionData(Platform, chat.Command{
+		Challenger: chat.Player{ID: "U1", Name: "alice"},
+		Target:     chat.Player{ID: "U2", Name: "bob"},
+		Workspace:  "T1",
+	})
+	otherWorkspace := chat.CreateSessionData(Platform, chat.Command{Workspace: "T2"})
+
+	sessions := []server.GameSession{
+		{Challenger: "U1", Target: "U2", ChallengerMove: "rock", TargetMove: "scissors", Result: game.GameStatePlayerOneWins, Data: data},
+		{Challenger: "U1", Target: "U2", ChallengerMove: "rock", TargetMove: "paper", Result: game.GameStatePlayerTwoWins, Data: data},
+		{Challenger: "U1", Target: "U2", ChallengerMove: "rock", TargetMove: "rock", Result: game.GameStateDraw, Data: data},
+		{Challenger: "U1", Target: "U2", ChallengerMove: "rock", Data: data},
+		{Challenger: "U1", Target: "U3", ChallengerMove: "rock", TargetMove: "scissors", Result: game.GameStatePlayerOneWins, Data: otherWorkspace},
+	}
+
+	view := homeView("T1", "U2", sessions)
+	stats := view.Blocks[0].(*SectionBlock).Text.Text
+	pending := view.Blocks[1].(*SectionBlock).Text.Text
+	results := view.Blocks[2].(*SectionBlock).Text.Text
+
+	if view.Type != ViewTypeHome || !strings.HasPrefix(stats, "*Your recent stats*") || !strings.Contains(stats, "Wins: 1   Losses: 1   Draws: 1") {
+		t.Fatalf("Unexpected stats: %q", stats)
+	}
+
+	if !strings.Contains(pending, "vs @alice: waiting for your move") {
+		t.Fatalf("Unexpected pending challenges: %q", pending)
+	}
+
+	if strings.Count(results, "•") != 3 || !strings.HasPrefix(results, "*Recent results*\n• @alice and @bob had a draw") {
+		t.Fatalf("Expected the most recent results first: %q", results)
+	}
+}
+
+func signedEventRequest(secret string, now time.Time, body string) *http.Request {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	r := httptest.NewRequest("POST", HandleEventsRoute, strings.NewReader(body))
+	r.Header.Set(timestampHeader, timestamp)
+	r.Header.Set(signatureHeader, "v0="+hex.EncodeToString(mac.Sum(nil)))
+
+	return r
+}
@@ -0,0 +1,108 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/logging"
+)
+
+// historyCommand is the slash command's first word for looking up the history of a session.
+const historyCommand = "history"
+
+// processHistory sends the audited history of the session to an admin.
+// Admins are recognized by the user_id of the command, so the command must have been verified as signed by Slack.
+func (controller *controller) processHistory(ctx context.Context, body Body, verified bool, args []string) {
+	switch {
+	case controller.history == nil:
+		controller.followUp(ctx, body, "The history of sessions isn't recorded by this server.")
+		return
+	case !verified:
+		controller.followUp(ctx, body, "The history of sessions can only be looked up once the app's signing secret is configured.")
+		return
+	case !controller.isAdmin(body.UserID):
+		controller.followUp(ctx, body, "Only admins can look up the history of a session.")
+		return
+	case len(args) != 1:
+		controller.followUp(ctx, body, "Please provide the ID of the session, e.g. `history <session>`.")
+		return
+	}
+
+	entries, err := controller.history.History(args[0])
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to read the history", logging.SessionKey, args[0], "error", err)
+		controller.followUp(ctx, body, "There was a problem reading the history. Please try again.")
+		return
+	}
+
+	if len(entries) == 0 {
+		controller.followUp(ctx, body, fmt.Sprintf("No history was recorded for the session %v.", args[0]))
+		return
+	}
+
+	lines := []string{fmt.Sprintf("*History of the session %v*", args[0])}
+	for _, entry := range entries {
+		lines = append(lines, formatEntry(entry, controller.defaultMode))
+	}
+
+	controller.followUp(ctx, body, strings.Join(lines, "\n"))
+}
+
+func (controller *controller) isAdmin(user string) bool {
+	for _, admin := range controller.admins {
+		if admin == user {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatEntry renders an audit entry as a line of the history, mentioning the players involved.
+// Sessions created without a mode are shown with defaultMode, the name of the GameServer's Game.
+func formatEntry(entry audit.Entry, defaultMode string) string {
+	var description string
+
+	switch entry.Type {
+	case audit.EntryCreated:
+		mode := entry.Mode
+		if mode == "" {
+			mode = defaultMode
+		}
+		description = fmt.Sprintf("<@%v> challenged <@%v> (%v)", entry.Challenger, entry.Target, mode)
+	case audit.EntryMove:
+		description = fmt.Sprintf("<@%v> played %v", entry.Actor, entry.Move)
+	case audit.EntryMoveRejected:
+		description = fmt.Sprintf("<@%v> tried to play %v: %v", entry.Actor, entry.Move, entry.Error)
+	case audit.EntryResult:
+		description = fmt.Sprintf("<@%v> played %v, <@%v> played %v: ", entry.Challenger, entry.ChallengerMove, entry.Target, entry.TargetMove)
+		switch entry.Result {
+		case game.GameStatePlayerOneWins:
+			description += fmt.Sprintf("<@%v> won", entry.Challenger)
+		case game.GameStatePlayerTwoWins:
+			description += fmt.Sprintf("<@%v> won", entry.Target)
+		default:
+			description += entry.Result
+		}
+	case audit.EntryExpired:
+		description = "The session expired"
+		if entry.Actor != "" {
+			description = "The session was expired by an admin"
+		}
+	case audit.EntryCancelled:
+		description = "The session was cancelled by an admin"
+		if entry.Actor != "" {
+			description = fmt.Sprintf("<@%v> cancelled the session", entry.Actor)
+		}
+	case audit.EntryReplayed:
+		description = "The result was posted again by an admin"
+	default:
+		description = entry.Type
+	}
+
+	return fmt.Sprintf("• %v %v", entry.Timestamp.Format("2006-01-02 15:04:05 MST"), description)
+}
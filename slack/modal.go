@@ -0,0 +1,176 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/game"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/logging"
+)
+
+const (
+	viewSubmissionPayload = "view_submission"
+
+	challengeViewCallbackID = "rps_challenge"
+	opponentBlockID         = "opponent"
+	opponentActionID        = "opponent_select"
+	modeBlockID             = "mode"
+	modeActionID            = "mode_select"
+	bestOfBlockID           = "best_of"
+	bestOfActionID          = "best_of_select"
+)
+
+// matchLengths are the number of games a challenge created through the modal can be played over.
+var matchLengths = []int{1, 3, 5, 7}
+
+// openChallengeView opens the challenge modal, used when the slash command is issued without an opponent.
+func (controller *controller) openChallengeView(ctx context.Context, body Body) {
+	client, err := controller.clients(workspaceKey(body.EnterpriseID, body.TeamID))
+	if err != nil {
+		controller.followUp(ctx, body, err.Error())
+		return
+	}
+
+	err = client.OpenView(body.TriggerID, challengeView(body.ChannelID))
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to open the challenge view", "error", err)
+		controller.followUp(ctx, body, chat.ErrorMessage(err, "There was a problem opening the challenge dialog."))
+	}
+}
+
+// processViewSubmission creates a challenge from the values submitted through the challenge modal.
+func (controller *controller) processViewSubmission(ctx context.Context, payload Payload, w http.ResponseWriter) {
+	if payload.View == nil || payload.View.CallbackID != challengeViewCallbackID {
+		return
+	}
+
+	values := payload.View.State.Values
+	target := values[opponentBlockID][opponentActionID].SelectedUser
+	mode := selectedOption(values[modeBlockID][modeActionID])
+	bestOf, _ := strconv.Atoi(selectedOption(values[bestOfBlockID][bestOfActionID]))
+
+	if _, ok := modes.RegisteredGames[mode]; !ok {
+		writeViewErrors(w, map[string]string{modeBlockID: "Please select one of the available game modes."})
+		return
+	}
+	if !validMatchLength(bestOf) {
+		writeViewErrors(w, map[string]string{bestOfBlockID: "Please select one of the available match lengths."})
+		return
+	}
+	if target == payload.User.ID {
+		writeViewErrors(w, map[string]string{opponentBlockID: "You need to challenge another person."})
+		return
+	}
+
+	workspace := workspaceKey(payload.Team.EnterpriseID, payload.Team.ID)
+	client, err := controller.clients(workspace)
+	if err != nil {
+		writeViewErrors(w, map[string]string{opponentBlockID: err.Error()})
+		return
+	}
+
+	targetInfo, err := client.GetUserInfo(target)
+	if err != nil {
+		writeViewErrors(w, map[string]string{opponentBlockID: "The selected user isn't a valid Slack user for this team."})
+		return
+	}
+
+	command := chat.Command{
+		Challenger: chat.Player{ID: payload.User.ID, Name: payload.User.Name},
+		Target:     chat.Player{ID: target, Name: targetInfo.Name},
+		Channel:    payload.View.PrivateMetadata,
+		Workspace:  workspace,
+		Mode:       mode,
+		BestOf:     bestOf,
+	}
+
+	ctx = logging.Detach(ctx)
+	enqueued := controller.queue.Enqueue("challenge", func() {
+		if challenge, err := controller.StartChallenge(command); err != nil {
+			slog.ErrorContext(ctx, "Failed to issue the challenge", logging.SessionKey, challenge.SessionID, "error", err)
+			text := chat.ErrorMessage(err, "There was a problem issuing the challenge. Please try again.")
+			if err := client.PostEphemeral(command.Channel, command.Challenger.ID, Message{Text: text}); err != nil {
+				slog.ErrorContext(ctx, "Failed to send the challenge error", "error", err)
+			}
+		}
+	})
+	if !enqueued {
+		writeViewErrors(w, map[string]string{opponentBlockID: busyText})
+	}
+}
+
+// challengeView renders the challenge modal. The channel is kept as the view's private metadata,
+// as view_submission payloads aren't sent with the channel the modal was opened from.
+func challengeView(channel string) *View {
+	var modeNames []string
+	for name := range modes.RegisteredGames {
+		modeNames = append(modeNames, name)
+	}
+	sort.Strings(modeNames)
+
+	modeSelect := NewSelectElement(ElementTypeStaticSelect, modeActionID, "Select a game mode")
+	for _, name := range modeNames {
+		option := NewOptionObject(game.DisplayName(name), name)
+		modeSelect.Options = append(modeSelect.Options, option)
+		if name == "standard" {
+			modeSelect.InitialOption = option
+		}
+	}
+
+	bestOfSelect := NewSelectElement(ElementTypeStaticSelect, bestOfActionID, "Select a match length")
+	for _, length := range matchLengths {
+		label := "Single game"
+		if length > 1 {
+			label = fmt.Sprintf("Best of %v", length)
+		}
+		bestOfSelect.Options = append(bestOfSelect.Options, NewOptionObject(label, strconv.Itoa(length)))
+	}
+	bestOfSelect.InitialOption = bestOfSelect.Options[0]
+
+	return &View{
+		Type:       ViewTypeModal,
+		CallbackID: challengeViewCallbackID,
+		Title:      NewTextObject(TextTypePlain, "Challenge to RPS"),
+		Submit:     NewTextObject(TextTypePlain, "Challenge"),
+		Close:      NewTextObject(TextTypePlain, "Cancel"),
+		Blocks: []Block{
+			NewInputBlock(opponentBlockID, "Opponent", NewSelectElement(ElementTypeUsersSelect, opponentActionID, "Select a user")),
+			NewInputBlock(modeBlockID, "Game mode", modeSelect),
+			NewInputBlock(bestOfBlockID, "Match length", bestOfSelect),
+		},
+		PrivateMetadata: channel,
+	}
+}
+
+func selectedOption(value ViewStateValue) string {
+	if value.SelectedOption == nil {
+		return ""
+	}
+
+	return value.SelectedOption.Value
+}
+
+func validMatchLength(bestOf int) bool {
+	for _, length := range matchLengths {
+		if length == bestOf {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeViewErrors(w http.ResponseWriter, errors map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ViewSubmissionResponse{ResponseAction: "errors", Errors: errors}); err != nil {
+		slog.Error("Failed to write the view errors", "error", err)
+	}
+}
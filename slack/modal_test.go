@@ -0,0 +1,71 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+func TestChallengeView(t *testing.T) {
+	view := challengeView("C1")
+
+	if view.CallbackID != challengeViewCallbackID || view.PrivateMetadata != "C1" || len(view.Blocks) != 3 {
+		t.Fatalf("Unexpected challenge view: %+v", view)
+	}
+
+	modeSelect := view.Blocks[1].(*InputBlock).Element
+	if len(modeSelect.Options) != len(modes.RegisteredGames) || modeSelect.InitialOption.Value != "standard" {
+		t.Fatalf("Expected an option for each registered game, got %+v", modeSelect.Options)
+	}
+
+	bestOfSelect := view.Blocks[2].(*InputBlock).Element
+	if len(bestOfSelect.Options) != len(matchLengths) || bestOfSelect.InitialOption.Value != "1" {
+		t.Fatalf("Expected an option for each match length, got %+v", bestOfSelect.Options)
+	}
+}
+
+func TestViewSubmissionValidatesValues(t *testing.T) {
+	gameServer := server.NewGameServer(modes.StandardGame)
+	controller := &controller{Service: &chat.Service{GameServer: gameServer}}
+
+	for blockID, values := range map[string][3]string{
+		opponentBlockID: {"U1", "standard", "3"},
+		modeBlockID:     {"U2", "unknown", "3"},
+		bestOfBlockID:   {"U2", "standard", "4"},
+	} {
+		var response ViewSubmissionResponse
+
+		w := httptest.NewRecorder()
+		controller.processViewSubmission(context.Background(), viewSubmission("U1", values[0], values[1], values[2]), w)
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response.ResponseAction != "errors" || response.Errors[blockID] == "" {
+			t.Fatalf("Expected an error for the %v input, got %q", blockID, w.Body.String())
+		}
+	}
+
+	if len(gameServer.GameSessionsManager.GameSessions) != 0 {
+		t.Fatal("Invalid submissions should not create sessions")
+	}
+}
+
+func viewSubmission(user, target, mode, bestOf string) Payload {
+	return Payload{
+		Type: viewSubmissionPayload,
+		User: User{ID: user, Name: user},
+		View: &PayloadView{
+			CallbackID:      challengeViewCallbackID,
+			PrivateMetadata: "C1",
+			State: ViewState{Values: map[string]map[string]ViewStateValue{
+				opponentBlockID: {opponentActionID: {Type: ElementTypeUsersSelect, SelectedUser: target}},
+				modeBlockID:     {modeActionID: {Type: ElementTypeStaticSelect, SelectedOption: NewOptionObject(mode, mode)}},
+				bestOfBlockID:   {bestOfActionID: {Type: ElementTypeStaticSelect, SelectedOption: NewOptionObject(bestOf, bestOf)}},
+			}},
+		},
+	}
+}
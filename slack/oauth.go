@@ -0,0 +1,181 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamologist/rps/logging"
+)
+
+const (
+	// AuthorizeRoute is the route users are sent to for installing the app in their workspace.
+	AuthorizeRoute = "https://slack.com/oauth/v2/authorize"
+
+	// BotScopes are the scopes requested when the app is installed.
+	BotScopes = "commands,chat:write,users:read,app_mentions:read"
+
+	// stateLifetime is how long a user has to complete the install flow.
+	stateLifetime = 10 * time.Minute
+	// stateCookie binds the install flow to the browser that started it, holding the nonce signed into the state.
+	stateCookie = "rps_oauth_state"
+)
+
+// OAuthAccessResponse is the response of the oauth.v2.access API method.
+type OAuthAccessResponse struct {
+	APIResponse
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope"`
+	BotUserID   string `json:"bot_user_id"`
+	AppID       string `json:"app_id"`
+	Team        Team   `json:"team"`
+	Enterprise  *Team  `json:"enterprise"` // Only set for workspaces of an Enterprise Grid organization.
+}
+
+type oauthController struct {
+	baseURL      string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	commandName  string
+	tokens       TokenStore
+}
+
+// HandleInstall redirects the user to Slack for installing the app in their workspace.
+func (controller *oauthController) HandleInstall(w http.ResponseWriter, r *http.Request) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to generate the OAuth state", "error", err)
+		http.Error(w, "An error occurred while installing the RPS app.", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    hex.EncodeToString(nonce),
+		Path:     OAuthRedirectRoute,
+		MaxAge:   int(stateLifetime.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := url.Values{
+		"client_id": {controller.clientID},
+		"scope":     {BotScopes},
+		"state":     {controller.newState(hex.EncodeToString(nonce), time.Now())},
+	}
+	if controller.redirectURL != "" {
+		query.Set("redirect_uri", controller.redirectURL)
+	}
+
+	http.Redirect(w, r, AuthorizeRoute+"?"+query.Encode(), http.StatusFound)
+}
+
+// HandleRedirect completes an installation, storing the workspace's bot token.
+func (controller *oauthController) HandleRedirect(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Get("error") != "" {
+		http.Error(w, "The RPS app was not installed: "+query.Get("error"), http.StatusBadRequest)
+		return
+	}
+
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil || !controller.validState(query.Get("state"), cookie.Value, time.Now()) {
+		http.Error(w, "The installation link has expired. Please start the installation again.", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: stateCookie, Path: OAuthRedirectRoute, MaxAge: -1})
+
+	access, err := controller.exchange(query.Get("code"))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to exchange the OAuth code", "error", err)
+		http.Error(w, "An error occurred while installing the RPS app.", http.StatusBadGateway)
+		return
+	}
+
+	var enterpriseID string
+	if access.Enterprise != nil {
+		enterpriseID = access.Enterprise.ID
+	}
+
+	if err := controller.tokens.SetToken(workspaceKey(enterpriseID, access.Team.ID), access.AccessToken); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to store the bot token", logging.TeamKey, access.Team.ID, "error", err)
+		http.Error(w, "An error occurred while installing the RPS app.", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintf(w, "The RPS app has been installed in %v. Use /%v @someone to start a game.", access.Team.Name, controller.commandName)
+}
+
+// exchange trades the code Slack redirected the user with for the workspace's bot token.
+func (controller *oauthController) exchange(code string) (OAuthAccessResponse, error) {
+	var access OAuthAccessResponse
+
+	form := url.Values{
+		"client_id":     {controller.clientID},
+		"client_secret": {controller.clientSecret},
+		"code":          {code},
+	}
+	if controller.redirectURL != "" {
+		form.Set("redirect_uri", controller.redirectURL)
+	}
+
+	resp, err := httpClient.PostForm(controller.baseURL+"/"+OAuthAccessMethod, form)
+	if err != nil {
+		return access, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&access); err != nil {
+		return access, err
+	}
+	if !access.OK {
+		return access, fmt.Errorf("Slack responded with an error: %v", access.Error)
+	}
+
+	return access, nil
+}
+
+// newState returns the state passed through the install flow, protecting the redirect from forged requests.
+// The state is a timestamp and the nonce of the browser's stateCookie, signed using the client secret,
+// so no server side storage is needed while the redirect is only accepted from the browser that started the flow.
+func (controller *oauthController) newState(nonce string, now time.Time) string {
+	value := strconv.FormatInt(now.Unix(), 10) + "." + nonce
+	return value + "." + controller.sign(value)
+}
+
+func (controller *oauthController) validState(state, nonce string, now time.Time) bool {
+	tokens := strings.SplitN(state, ".", 3)
+	if len(tokens) != 3 || !hmac.Equal([]byte(tokens[2]), []byte(controller.sign(tokens[0]+"."+tokens[1]))) {
+		return false
+	}
+	if nonce == "" || !hmac.Equal([]byte(tokens[1]), []byte(nonce)) {
+		return false
+	}
+
+	timestamp, err := strconv.ParseInt(tokens[0], 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return now.Sub(time.Unix(timestamp, 0)) <= stateLifetime
+}
+
+func (controller *oauthController) sign(value string) string {
+	mac := hmac.New(sha256.New, []byte(controller.clientSecret))
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
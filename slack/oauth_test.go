@@ -0,0 +1,138 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstallRedirectsToSlack(t *testing.T) {
+	controller := &oauthController{clientID: "client", clientSecret: "secret", tokens: NewMemoryTokenStore()}
+	w := httptest.NewRecorder()
+	controller.HandleInstall(w, httptest.NewRequest("GET", InstallRoute, nil))
+
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if w.Code != http.StatusFound || !strings.HasPrefix(location.String(), AuthorizeRoute) {
+		t.Fatalf("Expected a redirect to Slack, got %v %v", w.Code, location)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != stateCookie || !cookies[0].HttpOnly {
+		t.Fatalf("Expected the state cookie to be set, got %v", cookies)
+	}
+
+	query := location.Query()
+	if query.Get("client_id") != "client" || query.Get("scope") != BotScopes || !controller.validState(query.Get("state"), cookies[0].Value, time.Now()) {
+		t.Fatalf("Unexpected authorize parameters: %v", query)
+	}
+}
+
+func TestOAuthStateExpiresAndCantBeForged(t *testing.T) {
+	controller := &oauthController{clientSecret: "secret"}
+	forger := &oauthController{clientSecret: "other"}
+	now := time.Now()
+
+	if controller.validState(controller.newState("nonce", now.Add(-stateLifetime-time.Second)), "nonce", now) {
+		t.Fatal("Expired state should be rejected")
+	}
+
+	if controller.validState(forger.newState("nonce", now), "nonce", now) || controller.validState("", "", now) {
+		t.Fatal("Forged state should be rejected")
+	}
+}
+
+func TestOAuthStateIsBoundToTheBrowser(t *testing.T) {
+	controller := &oauthController{clientSecret: "secret"}
+	state := controller.newState("nonce", time.Now())
+
+	if !controller.validState(state, "nonce", time.Now()) {
+		t.Fatal("State should be accepted from the browser that started the install")
+	}
+
+	if controller.validState(state, "other", time.Now()) || controller.validState(state, "", time.Now()) {
+		t.Fatal("State should be rejected from another browser")
+	}
+}
+
+func TestRedirectRejectsInvalidState(t *testing.T) {
+	tokens := NewMemoryTokenStore()
+	controller := &oauthController{clientID: "client", clientSecret: "secret", tokens: tokens}
+
+	state := url.QueryEscape(controller.newState("nonce", time.Now()))
+	for _, query := range []string{"?code=code&state=forged", "?error=access_denied", "?code=code&state=" + state} {
+		w := httptest.NewRecorder()
+		controller.HandleRedirect(w, httptest.NewRequest("GET", OAuthRedirectRoute+query, nil))
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %v, got %v", http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestFileTokenStorePersistsTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	store, err := NewFileTokenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to create the token store: %q", err)
+	}
+
+	if err := store.SetToken("T1", "xoxb-1"); err != nil {
+		t.Fatalf("Failed to store the token: %q", err)
+	}
+
+	store, err = NewFileTokenStore(path)
+	if err != nil {
+		t.Fatalf("Failed to load the token store: %q", err)
+	}
+
+	if token, ok := store.Token("T1"); !ok || token != "xoxb-1" {
+		t.Fatalf("Expected the stored token, got %q", token)
+	}
+}
+
+func TestFileTokenStoreKeepsTokensThatFailedToPersist(t *testing.T) {
+	store, err := NewFileTokenStore(filepath.Join(t.TempDir(), "missing", "tokens.json"))
+	if err != nil {
+		t.Fatalf("Failed to create the token store: %q", err)
+	}
+
+	if err := store.SetToken("T1", "xoxb-1"); err == nil {
+		t.Fatal("Expected the token to fail to persist")
+	}
+	if _, ok := store.Token("T1"); ok {
+		t.Fatal("Expected a token that wasn't persisted to be kept out of memory")
+	}
+}
+
+func TestWorkspaceKeyIncludesEnterprise(t *testing.T) {
+	if workspaceKey("", "T1") != "T1" {
+		t.Fatal("Workspaces outside of Enterprise Grid should be keyed by their team id")
+	}
+
+	if workspaceKey("E1", "T1") == workspaceKey("E2", "T1") {
+		t.Fatal("Workspaces of different organizations should not share a key")
+	}
+}
+
+func TestClientFactoryUsesWorkspaceTokens(t *testing.T) {
+	tokens := NewMemoryTokenStore()
+	tokens.SetToken("T1", "xoxb-1")
+
+	clients := NewClientFactory(DefaultBaseURL, tokens, "")
+	if client, err := clients("T1"); err != nil || client.(*apiClient).token != "xoxb-1" {
+		t.Fatalf("Expected the workspace's token, got %+v: %q", client, err)
+	}
+
+	if _, err := clients("T2"); err != ErrNotInstalled {
+		t.Fatalf("Expected %q, got %q", ErrNotInstalled, err)
+	}
+
+	clients = NewClientFactory(DefaultBaseURL, tokens, "xoxb-default")
+	if client, err := clients("T2"); err != nil || client.(*apiClient).token != "xoxb-default" {
+		t.Fatalf("Expected the default token, got %+v: %q", client, err)
+	}
+}
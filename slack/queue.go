@@ -0,0 +1,98 @@
+package slack
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/hamologist/rps/metrics"
+)
+
+const (
+	defaultQueueWorkers = 4
+	defaultQueueSize    = 100
+)
+
+var (
+	queueDepth = metrics.DefaultRegistry.NewGauge("rps_slack_queue_depth", "Number of jobs waiting in the Slack work queue.")
+	queueJobs  = metrics.DefaultRegistry.NewCounterVec(
+		"rps_slack_queue_jobs_total",
+		"Number of jobs submitted to the Slack work queue, by job and whether they were processed, panicked or dropped.",
+		"job", "status",
+	)
+	queueWait = metrics.DefaultRegistry.NewHistogramVec(
+		"rps_slack_queue_wait_seconds",
+		"Time jobs spent waiting in the Slack work queue.",
+		metrics.DefaultBuckets,
+		"job",
+	)
+	queueRun = metrics.DefaultRegistry.NewHistogramVec(
+		"rps_slack_queue_run_seconds",
+		"Time jobs spent running once they left the Slack work queue.",
+		metrics.DefaultBuckets,
+		"job",
+	)
+)
+
+type queuedJob struct {
+	name     string
+	run      func()
+	enqueued time.Time
+}
+
+// workQueue runs jobs on a fixed number of workers, so handlers can acknowledge Slack within its 3 second deadline.
+type workQueue struct {
+	jobs chan queuedJob
+}
+
+func newWorkQueue(workers, size int) *workQueue {
+	queue := &workQueue{jobs: make(chan queuedJob, size)}
+
+	for i := 0; i < workers; i++ {
+		go queue.work()
+	}
+
+	return queue
+}
+
+// Enqueue adds the job to the queue without blocking, returning false when the queue is full.
+func (queue *workQueue) Enqueue(name string, run func()) bool {
+	// Counted before the send, so a worker taking the job straight away can't take the depth below zero.
+	queueDepth.Add(1)
+	select {
+	case queue.jobs <- queuedJob{name: name, run: run, enqueued: time.Now()}:
+		return true
+	default:
+		queueDepth.Add(-1)
+		queueJobs.Inc(name, "dropped")
+		slog.Warn("The Slack work queue is full, dropped a job", "job", name)
+		return false
+	}
+}
+
+func (queue *workQueue) work() {
+	for job := range queue.jobs {
+		queueDepth.Add(-1)
+		started := time.Now()
+		queueWait.Observe(started.Sub(job.enqueued).Seconds(), job.name)
+
+		status := queue.run(job)
+
+		queueRun.Observe(time.Since(started).Seconds(), job.name)
+		queueJobs.Inc(job.name, status)
+	}
+}
+
+// run runs the job, recovering from a panic so the worker keeps processing the queue.
+func (queue *workQueue) run(job queuedJob) (status string) {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Error("A Slack job panicked", "job", job.name, "panic", err, "stack", string(debug.Stack()))
+			status = "panicked"
+		}
+	}()
+
+	job.run()
+
+	return "processed"
+}
@@ -0,0 +1,51 @@
+package slack
+
+import "testing"
+
+func TestWorkQueueDropsJobsWhenFull(t *testing.T) {
+	queue := newWorkQueue(1, 1)
+	started := make(chan bool)
+	release := make(chan bool)
+	done := make(chan bool, 2)
+
+	queue.Enqueue("blocking", func() {
+		started <- true
+		<-release
+		done <- true
+	})
+	<-started
+
+	dropped := queueJobs.Value("dropped", "dropped")
+	if !queue.Enqueue("queued", func() { done <- true }) {
+		t.Fatal("Expected the job to fit in the queue")
+	}
+	if queue.Enqueue("dropped", func() {}) {
+		t.Fatal("Expected the job to be dropped once the queue is full")
+	}
+	if queueJobs.Value("dropped", "dropped") != dropped+1 {
+		t.Fatal("Expected the dropped job to be counted")
+	}
+
+	processed := queueJobs.Value("blocking", "processed")
+	close(release)
+	<-done
+	<-done
+
+	if queueJobs.Value("blocking", "processed") != processed+1 {
+		t.Fatal("Expected processed jobs to be counted")
+	}
+}
+
+func TestWorkQueueRecoversFromPanics(t *testing.T) {
+	queue := newWorkQueue(1, 2)
+	done := make(chan bool)
+
+	panicked := queueJobs.Value("panicking", "panicked")
+	queue.Enqueue("panicking", func() { panic("job failed") })
+	queue.Enqueue("following", func() { done <- true })
+	<-done
+
+	if queueJobs.Value("panicking", "panicked") != panicked+1 {
+		t.Fatal("Expected the panicked job to be counted")
+	}
+}
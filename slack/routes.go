@@ -1,18 +1,85 @@
 package slack
 
-import "github.com/hamologist/rps/server"
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/config"
+	"github.com/hamologist/rps/server"
+)
 
 const (
 	// HandleGameRequestRoute defines the routes used by the HandleGameRequest controller method
 	HandleGameRequestRoute = "/slack/rps"
 	// HandleGamePayloadRoute defines the routes used by the HandleGamePayload controller method
 	HandleGamePayloadRoute = "/slack/rps/payload"
+	// HandleEventsRoute defines the route used by the HandleEvents controller method.
+	// It should be configured as the app's Events API request URL.
+	HandleEventsRoute = "/slack/events"
+	// InstallRoute defines the route used for installing the app in a workspace.
+	InstallRoute = "/slack/install"
+	// OAuthRedirectRoute defines the route Slack redirects to once the app was installed.
+	// It should be configured as the app's redirect URL.
+	OAuthRedirectRoute = "/slack/oauth/redirect"
 )
 
-func registerRoutes(gameServer *server.GameServer) {
-	controller := newController(gameServer)
+func registerRoutes(gameServer *server.GameServer, cfg config.Config, tokens TokenStore, clients ClientFactory, history audit.Reader) {
+	controller := newController(gameServer, clients, cfg, history)
 	serveMux := controller.ServeMux
 
 	serveMux.HandleFunc(HandleGameRequestRoute, controller.HandleGameRequest)
 	serveMux.HandleFunc(HandleGamePayloadRoute, controller.HandleGamePayload)
+	gameServer.GameSessionsManager.Events.Listen(controller.handleEvent)
+
+	// Workspaces installed through OAuth have their own tokens, so only the default token can be verified up front.
+	if cfg.Slack.OAuthToken != "" {
+		gameServer.AddReadinessCheck("slack", tokenCheck(NewClient(cfg.Slack.BaseURL, cfg.Slack.OAuthToken)))
+	}
+
+	if cfg.Slack.SigningSecret != "" {
+		serveMux.HandleFunc(HandleEventsRoute, controller.HandleEvents)
+	}
+
+	if cfg.Slack.ClientID != "" && cfg.Slack.ClientSecret != "" {
+		oauthController := &oauthController{
+			baseURL:      cfg.Slack.BaseURL,
+			clientID:     cfg.Slack.ClientID,
+			clientSecret: cfg.Slack.ClientSecret,
+			redirectURL:  cfg.Slack.RedirectURL,
+			commandName:  cfg.Slack.CommandName,
+			tokens:       tokens,
+		}
+
+		serveMux.HandleFunc(InstallRoute, oauthController.HandleInstall)
+		serveMux.HandleFunc(OAuthRedirectRoute, oauthController.HandleRedirect)
+	}
+}
+
+// tokenCheckTTL is how long a successful token check is trusted, so readiness probes don't call Slack every time.
+const tokenCheckTTL = time.Minute
+
+// tokenCheck returns a server.ReadinessCheck verifying the client's token through auth.test.
+func tokenCheck(client SlackClient) server.ReadinessCheck {
+	var (
+		mutex    sync.Mutex
+		verified time.Time
+	)
+
+	return func(ctx context.Context) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if time.Since(verified) < tokenCheckTTL {
+			return nil
+		}
+
+		if err := client.AuthTest(); err != nil {
+			return err
+		}
+		verified = time.Now()
+
+		return nil
+	}
 }
@@ -1,76 +1,46 @@
 package slack
 
 import (
-	"fmt"
-	"os"
+	"log/slog"
 
-	"github.com/nlopes/slack"
-
-	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/audit"
+	"github.com/hamologist/rps/config"
 	"github.com/hamologist/rps/server"
 )
 
 const (
 	inChannelResponse          = "in_channel"
 	ephemeralResponse          = "ephemeral"
-	defaultAcceptCommandName   = "rps-accept"
 	gameSessionInitiatedStatus = "initiated"
 	gameSessionAcceptedStatus  = "accepted"
 )
 
-var (
-	// DefaultGameServer is the default game server created by the slack package.
-	// Used as an optional game server that supports everything needed for integrating with slack.
-	DefaultGameServer *server.GameServer
-
-	// OAuthToken is the access token needed for interacting with the Slack API.
-	OAuthToken string
-
-	// API is a package global for working with the Slack API
-	API *slack.Client
-)
-
-func createSlackData(channelName, challengerName, targetName string) map[string]string {
-	return map[string]string{
-		"channelName":    channelName,
-		"challengerName": challengerName,
-		"targetName":     targetName,
-	}
-}
-
-func validSlackData(data map[string]string) bool {
-	if _, ok := data["channelName"]; !ok {
-		return false
+// RegisterRoutes registers the Slack routes on the GameServer's ServeMux.
+// The bot tokens of installed workspaces are kept in the configured token file, or in memory when there is none.
+// The history of sessions is looked up in history for the configured admins, the history command is disabled when it's nil.
+func RegisterRoutes(gameServer *server.GameServer, cfg config.Config, history audit.Reader) error {
+	var tokens TokenStore = NewMemoryTokenStore()
+
+	if cfg.Slack.TokenFile != "" {
+		store, err := NewFileTokenStore(cfg.Slack.TokenFile)
+		if err != nil {
+			return err
+		}
+		tokens = store
 	}
 
-	if _, ok := data["challengerName"]; !ok {
-		return false
+	if cfg.Slack.BaseURL == "" {
+		cfg.Slack.BaseURL = DefaultBaseURL
 	}
 
-	if _, ok := data["targetName"]; !ok {
-		return false
-	}
-
-	return true
-}
-
-func init() {
-	rpsGame := os.Getenv("RPS_GAME")
-	OAuthToken = os.Getenv("RPS_SLACK_OAUTH")
-
-	if registeredGame, ok := modes.RegisteredGames[rpsGame]; ok {
-		DefaultGameServer = server.NewGameServer(registeredGame)
-	} else {
-		DefaultGameServer = server.NewGameServer(modes.StandardGame)
-	}
-
-	if OAuthToken == "" {
-		fmt.Print(
-			"No slack OAuth token was provided, application will have limited slack support.\n" +
-				"Please consider providing an OAuth token using the \"RPS_SLACK_OAUTH\" env variable.\n",
+	if cfg.Slack.OAuthToken == "" && cfg.Slack.ClientID == "" {
+		slog.Warn("No Slack OAuth token or app credentials were provided, application will have limited Slack support",
+			"token_env", "RPS_SLACK_OAUTH",
+			"credentials_env", []string{"RPS_SLACK_CLIENT_ID", "RPS_SLACK_CLIENT_SECRET"},
 		)
 	}
-	API = slack.New(OAuthToken)
 
-	registerRoutes(DefaultGameServer)
+	registerRoutes(gameServer, cfg, tokens, NewClientFactory(cfg.Slack.BaseURL, tokens, cfg.Slack.OAuthToken), history)
+
+	return nil
 }
@@ -0,0 +1,120 @@
+package slack
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TokenStore stores the bot token of every workspace the app is installed in, keyed by the workspace's workspaceKey.
+type TokenStore interface {
+	Token(workspace string) (string, bool)
+	SetToken(workspace, token string) error
+}
+
+// workspaceKey identifies a workspace by its team id, prefixed by the id of its Enterprise Grid organization if any.
+// Team ids are only unique within an organization, so workspaces are never identified by their team id alone.
+func workspaceKey(enterpriseID, teamID string) string {
+	if enterpriseID == "" {
+		return teamID
+	}
+
+	return enterpriseID + ":" + teamID
+}
+
+type memoryTokenStore struct {
+	mutex  sync.Mutex
+	tokens map[string]string
+}
+
+// NewMemoryTokenStore creates a TokenStore that forgets every installation once the application stops.
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{
+		tokens: make(map[string]string),
+	}
+}
+
+func (store *memoryTokenStore) Token(workspace string) (string, bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	token, ok := store.tokens[workspace]
+	return token, ok
+}
+
+func (store *memoryTokenStore) SetToken(workspace, token string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.tokens[workspace] = token
+	return nil
+}
+
+type fileTokenStore struct {
+	memoryTokenStore
+	path string
+}
+
+// NewFileTokenStore creates a TokenStore persisted as JSON to the file at the provided path.
+// The file is only readable by the current user, as the tokens grant access to every installed workspace.
+func NewFileTokenStore(path string) (TokenStore, error) {
+	store := &fileTokenStore{
+		memoryTokenStore: memoryTokenStore{tokens: make(map[string]string)},
+		path:             path,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.tokens); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (store *fileTokenStore) SetToken(workspace, token string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	// The tokens are only replaced once written, so memory never holds an installation the file lost.
+	tokens := make(map[string]string, len(store.tokens)+1)
+	for key, value := range store.tokens {
+		tokens[key] = value
+	}
+	tokens[workspace] = token
+
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	// Written to a temporary file first, so a failed write never loses the existing installations.
+	tmp, err := ioutil.TempFile(filepath.Dir(store.path), filepath.Base(store.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), store.path); err != nil {
+		return err
+	}
+
+	store.tokens = tokens
+	return nil
+}
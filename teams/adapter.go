@@ -0,0 +1,204 @@
+package teams
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/server"
+)
+
+const (
+	// Platform is the chat platform name stored with sessions created through Teams.
+	Platform = "teams"
+
+	authorizationHeader = "Authorization"
+	hmacScheme          = "HMAC "
+
+	// maxActivitySize caps the size of the activities read from Teams.
+	maxActivitySize = 1 << 20
+)
+
+var mentionPattern = regexp.MustCompile(`<at>.*?</at>`)
+
+// adapter implements chat.Adapter for Teams outgoing webhooks.
+// Outgoing webhooks can only respond in the channel, so each player is privately sent a link to the browser UI by the Teams bot.
+type adapter struct {
+	gameServer  *server.GameServer
+	secret      []byte
+	publicURL   string
+	webhookURL  string
+	connector   *connector
+	mutex       sync.Mutex
+	serviceURLs map[string]string // The connector used by each tenant, as found in the tenant's activities.
+}
+
+func (adapter *adapter) Platform() string {
+	return Platform
+}
+
+func (adapter *adapter) ParseCommand(r *http.Request) (chat.Command, error) {
+	var activity Activity
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxActivitySize))
+	if err != nil || !adapter.verify(r.Header.Get(authorizationHeader), body) {
+		return chat.Command{}, chat.ErrUnauthorized
+	}
+
+	if err := json.Unmarshal(body, &activity); err != nil || activity.From == nil {
+		return chat.Command{}, errors.New("An error occurred while proccessing your message")
+	}
+
+	var target *Account
+	for _, entity := range activity.Entities {
+		if entity.Type != EntityTypeMention || entity.Mentioned == nil {
+			continue
+		}
+		if activity.Recipient != nil && entity.Mentioned.ID == activity.Recipient.ID {
+			continue
+		}
+
+		target = entity.Mentioned
+		break
+	}
+
+	if target == nil {
+		return chat.Command{}, errors.New("Mention the user you want to challenge along with the RPS webhook.")
+	}
+	if target.ID == activity.From.ID {
+		return chat.Command{}, errors.New("You need to challenge another person.")
+	}
+
+	command := chat.Command{
+		Challenger: chat.Player{ID: activity.From.ID, Name: activity.From.Name},
+		Target:     chat.Player{ID: target.ID, Name: target.Name},
+	}
+
+	// Any text following the mentions names the game mode.
+	textTokens := strings.Fields(mentionPattern.ReplaceAllString(activity.Text, ""))
+	if len(textTokens) >= 1 {
+		if command.Mode, err = chat.ParseMode(textTokens[0]); err != nil {
+			return chat.Command{}, err
+		}
+	}
+	if activity.Conversation != nil {
+		command.Channel = activity.Conversation.ID
+	}
+
+	// Personal messages are sent to the players through the connector of the tenant the challenge came from.
+	if activity.ChannelData == nil || activity.ChannelData.Tenant == nil || activity.ServiceURL == "" {
+		return chat.Command{}, errors.New("Challenges can only be issued from a Teams channel.")
+	}
+	command.Workspace = activity.ChannelData.Tenant.ID
+
+	adapter.mutex.Lock()
+	adapter.serviceURLs[command.Workspace] = activity.ServiceURL
+	adapter.mutex.Unlock()
+
+	return command, nil
+}
+
+// RenderChallenge announces the challenge in the channel, the players' links are sent privately.
+func (adapter *adapter) RenderChallenge(w http.ResponseWriter, challenge chat.Challenge) error {
+	writeMessage(w, fmt.Sprintf(
+		"**%v** has challenged **%v** to a game of RPS. Both players have been sent a private link for selecting their move.",
+		challenge.Challenger.Name,
+		challenge.Target.Name,
+	))
+
+	return nil
+}
+
+// SendPrivatePrompt sends the player a personal message with their own link to the browser UI.
+func (adapter *adapter) SendPrivatePrompt(challenge chat.Challenge, player chat.Player) error {
+	adapter.mutex.Lock()
+	serviceURL, ok := adapter.serviceURLs[challenge.Workspace]
+	adapter.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("No Teams connector is known for the tenant %v", challenge.Workspace)
+	}
+
+	link, err := adapter.gameServer.PlayerLink(challenge.SessionID, player.ID)
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("**%v** has challenged you to a game of RPS.", challenge.Challenger.Name)
+	if player.ID == challenge.Challenger.ID {
+		text = fmt.Sprintf("Your challenge was sent to **%v**.", challenge.Target.Name)
+	}
+	text += fmt.Sprintf(" [Select your move](%v), this link is only for you.", strings.TrimSuffix(adapter.publicURL, "/")+link)
+
+	return adapter.connector.SendPersonal(serviceURL, challenge.Workspace, player.ID, text)
+}
+
+// PostResult posts the result to the incoming webhook, when one was configured.
+// The connector's client is used, so a slow webhook can't hold up the chat.Service's result workers.
+func (adapter *adapter) PostResult(session server.GameSession) error {
+	if adapter.webhookURL == "" {
+		return nil
+	}
+
+	playResult := chat.FormatResult(session, func(player chat.Player) string {
+		return "**" + player.Name + "**"
+	})
+
+	body, err := json.Marshal(WebhookMessage{Text: playResult})
+	if err != nil {
+		return err
+	}
+
+	resp, err := adapter.connector.httpClient.Post(adapter.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Teams incoming webhook responded with status %v", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (adapter *adapter) RenderError(w http.ResponseWriter, message string) {
+	writeMessage(w, message)
+}
+
+// verify checks the HMAC-SHA256 signature Teams sends in the Authorization header.
+// The signature is computed over the request body using the base64 decoded webhook secret.
+func (adapter *adapter) verify(authorization string, body []byte) bool {
+	if !strings.HasPrefix(authorization, hmacScheme) {
+		return false
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authorization, hmacScheme))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, adapter.secret)
+	mac.Write(body)
+
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+func writeMessage(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Activity{Type: ActivityTypeMessage, Text: text}); err != nil {
+		slog.Error("Failed to write the Teams response", "error", err)
+	}
+}
@@ -0,0 +1,208 @@
+package teams
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamologist/rps/game/modes"
+	"github.com/hamologist/rps/server"
+)
+
+var secret = []byte("teams secret")
+
+func TestRejectsInvalidSignatures(t *testing.T) {
+	gameServer, _ := createMockServer(t, "")
+
+	r := signedRequest([]byte("other secret"), challengeActivity("bob"))
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized || len(gameServer.GameSessionsManager.GameSessions) != 0 {
+		t.Fatalf("Expected status %v, got %v", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRejectsChallengesWithoutTarget(t *testing.T) {
+	gameServer, _ := createMockServer(t, "")
+
+	for _, target := range []string{"", "alice"} {
+		response := doActivity(t, gameServer, challengeActivity(target))
+		if response.Type != ActivityTypeMessage || len(gameServer.GameSessionsManager.GameSessions) != 0 {
+			t.Fatalf("Expected the challenge to be rejected, got %+v", response)
+		}
+	}
+}
+
+func TestParsesGameModes(t *testing.T) {
+	gameServer, botFramework := createMockServer(t, "")
+
+	activity := botFramework.challengeActivity("bob")
+	activity.Text += " rpsls"
+	doActivity(t, gameServer, activity)
+
+	for _, v := range gameServer.GameSessionsManager.GameSessions {
+		if v.Mode != "rpsls" {
+			t.Fatalf("Expected an rpsls session, got %+v", v)
+		}
+	}
+
+	activity.Text += "unknown"
+	response := doActivity(t, gameServer, activity)
+	if !strings.Contains(response.Text, "Unknown game mode") || len(gameServer.GameSessionsManager.GameSessions) != 1 {
+		t.Fatalf("Expected the unknown mode to be rejected, got %q", response.Text)
+	}
+}
+
+func TestPlaysAGameThroughPlayerLinks(t *testing.T) {
+	results := make(chan WebhookMessage, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message WebhookMessage
+		json.NewDecoder(r.Body).Decode(&message)
+		results <- message
+	}))
+	defer webhook.Close()
+
+	gameServer, botFramework := createMockServer(t, webhook.URL)
+	gameServer.RegisterWebRoutes()
+
+	response := doActivity(t, gameServer, botFramework.challengeActivity("bob"))
+	if strings.Contains(response.Text, "/play/") {
+		t.Fatalf("Expected the player links to be kept out of the channel, got %q", response.Text)
+	}
+
+	links := make(map[string]string)
+	for range []string{"alice", "bob"} {
+		message := <-botFramework.messages
+		link := regexp.MustCompile(`\(https://rps\.example\.com(/play/[^)]+)\)`).FindStringSubmatch(message.text)
+		if link == nil {
+			t.Fatalf("Expected a link in the personal message, got %q", message.text)
+		}
+		links[message.user] = link[1]
+	}
+
+	for _, player := range []struct{ user, move string }{{"alice", "rock"}, {"bob", "scissors"}} {
+		link, _ := url.Parse(links[player.user])
+		r := httptest.NewRequest("POST", link.Path+"/move", strings.NewReader(url.Values{
+			"key":  {link.Query().Get("key")},
+			"move": {player.move},
+		}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		gameServer.ServeMux.ServeHTTP(w, r)
+
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("Expected the move to be accepted, got status %v", w.Code)
+		}
+	}
+
+	select {
+	case message := <-results:
+		if message.Text != "**alice** defeated **bob**, rock beats scissors" {
+			t.Fatalf("Unexpected result: %v", message.Text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the result to be posted")
+	}
+}
+
+type personalMessage struct {
+	user string
+	text string
+}
+
+// mockBotFramework is a fake Bot Framework connector, capturing the personal messages sent to players.
+type mockBotFramework struct {
+	*httptest.Server
+	messages chan personalMessage
+}
+
+// challengeActivity returns a challenge sent from a tenant served by the fake connector.
+func (botFramework *mockBotFramework) challengeActivity(target string) Activity {
+	activity := challengeActivity(target)
+	activity.ServiceURL = botFramework.URL
+
+	return activity
+}
+
+// createMockServer registers the Teams routes, sending personal messages through a mockBotFramework.
+func createMockServer(t *testing.T, webhookURL string) (*server.GameServer, *mockBotFramework) {
+	messages := make(chan personalMessage, 2)
+	botFramework := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 3600})
+		case r.URL.Path == "/v3/conversations":
+			var parameters ConversationParameters
+			json.NewDecoder(r.Body).Decode(&parameters)
+			json.NewEncoder(w).Encode(ConversationResource{ID: parameters.Members[0].ID})
+		case strings.HasSuffix(r.URL.Path, "/activities"):
+			var activity Activity
+			json.NewDecoder(r.Body).Decode(&activity)
+			messages <- personalMessage{user: strings.Split(r.URL.Path, "/")[3], text: activity.Text}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(botFramework.Close)
+
+	gameServer := server.NewGameServer(modes.StandardGame)
+	connector := newConnector(botFramework.URL+"/token", "app", "password")
+	registerRoutes(gameServer, newAdapter(gameServer, secret, "https://rps.example.com/", webhookURL, connector))
+
+	return gameServer, &mockBotFramework{Server: botFramework, messages: messages}
+}
+
+func signedRequest(key []byte, activity Activity) *http.Request {
+	body, _ := json.Marshal(activity)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+
+	r := httptest.NewRequest("POST", HandleMessageRoute, strings.NewReader(string(body)))
+	r.Header.Set(authorizationHeader, hmacScheme+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	return r
+}
+
+func doActivity(t *testing.T, gameServer *server.GameServer, activity Activity) Activity {
+	var response Activity
+
+	w := httptest.NewRecorder()
+	gameServer.ServeMux.ServeHTTP(w, signedRequest(secret, activity))
+	body, _ := ioutil.ReadAll(w.Body)
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("Invalid activity response %q: %q", body, err)
+	}
+
+	return response
+}
+
+func challengeActivity(target string) Activity {
+	activity := Activity{
+		Type:         ActivityTypeMessage,
+		Text:         "<at>RPS</at> <at>" + target + "</at>",
+		From:         &Account{ID: "alice", Name: "alice"},
+		Recipient:    &Account{ID: "rps-bot", Name: "RPS"},
+		Conversation: &Account{ID: "general"},
+		ServiceURL:   "https://smba.trafficmanager.net/emea/",
+		ChannelData:  &ChannelData{Tenant: &Account{ID: "tenant"}},
+		Entities: []Entity{
+			{Type: EntityTypeMention, Mentioned: &Account{ID: "rps-bot", Name: "RPS"}},
+		},
+	}
+	if target != "" {
+		activity.Entities = append(activity.Entities, Entity{Type: EntityTypeMention, Mentioned: &Account{ID: target, Name: target}})
+	}
+
+	return activity
+}
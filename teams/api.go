@@ -0,0 +1,59 @@
+// Package teams provides a Microsoft Teams outgoing webhook integration backed by a server.GameServer.
+// The same webhook format is used by other chat platforms, so the package doubles as a generic outgoing webhook adapter.
+package teams
+
+const (
+	// ActivityTypeMessage is the activity type for messages, both received and responded with.
+	ActivityTypeMessage = "message"
+	// EntityTypeMention is the entity type describing a user mentioned in a message.
+	EntityTypeMention = "mention"
+)
+
+// Activity provides a way to structure and work with the activities Teams sends to an outgoing webhook.
+type Activity struct {
+	Type         string       `json:"type"`
+	ID           string       `json:"id,omitempty"`
+	Text         string       `json:"text,omitempty"`
+	TextFormat   string       `json:"textFormat,omitempty"`
+	From         *Account     `json:"from,omitempty"`
+	Recipient    *Account     `json:"recipient,omitempty"`
+	Conversation *Account     `json:"conversation,omitempty"`
+	Entities     []Entity     `json:"entities,omitempty"`
+	ServiceURL   string       `json:"serviceUrl,omitempty"` // The Bot Framework connector of the activity's region.
+	ChannelData  *ChannelData `json:"channelData,omitempty"`
+}
+
+// ChannelData defines the Teams specific data sent with an Activity.
+type ChannelData struct {
+	Tenant *Account `json:"tenant,omitempty"`
+}
+
+// Account defines a user, bot or conversation taking part in an Activity.
+type Account struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Entity defines additional metadata sent with an Activity, such as the users mentioned in it.
+type Entity struct {
+	Type      string   `json:"type"`
+	Mentioned *Account `json:"mentioned,omitempty"`
+	Text      string   `json:"text,omitempty"`
+}
+
+// ConversationParameters is used for starting a personal conversation between the bot and a user.
+type ConversationParameters struct {
+	Bot         Account      `json:"bot"`
+	Members     []Account    `json:"members"`
+	ChannelData *ChannelData `json:"channelData,omitempty"`
+}
+
+// ConversationResource is the response of starting a conversation.
+type ConversationResource struct {
+	ID string `json:"id"`
+}
+
+// WebhookMessage is the payload accepted by an incoming webhook, used for posting results.
+type WebhookMessage struct {
+	Text string `json:"text"`
+}
@@ -0,0 +1,137 @@
+package teams
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TokenURL is where the bot's access tokens for the Bot Framework connector are requested.
+	TokenURL = "https://login.microsoftonline.com/botframework.com/oauth2/v2.0/token"
+
+	connectorScope   = "https://api.botframework.com/.default"
+	connectorTimeout = 10 * time.Second
+	// tokenRefreshMargin renews access tokens slightly before they expire.
+	tokenRefreshMargin = time.Minute
+)
+
+// connector sends personal messages through the Bot Framework connector, authenticated as the Teams bot.
+type connector struct {
+	tokenURL    string
+	appID       string
+	appPassword string
+	httpClient  *http.Client
+	mutex       sync.Mutex
+	token       string
+	expires     time.Time
+}
+
+func newConnector(tokenURL, appID, appPassword string) *connector {
+	return &connector{
+		tokenURL:    tokenURL,
+		appID:       appID,
+		appPassword: appPassword,
+		httpClient:  &http.Client{Timeout: connectorTimeout},
+	}
+}
+
+// SendPersonal messages the user in a one to one conversation with the bot, which only the user can see.
+// The serviceURL and tenantID come from an activity sent by the user's tenant.
+func (connector *connector) SendPersonal(serviceURL, tenantID, userID, text string) error {
+	var conversation ConversationResource
+
+	serviceURL = strings.TrimSuffix(serviceURL, "/")
+	err := connector.post(serviceURL+"/v3/conversations", ConversationParameters{
+		Bot:         Account{ID: "28:" + connector.appID},
+		Members:     []Account{{ID: userID}},
+		ChannelData: &ChannelData{Tenant: &Account{ID: tenantID}},
+	}, &conversation)
+	if err != nil {
+		return err
+	}
+
+	return connector.post(
+		serviceURL+"/v3/conversations/"+url.PathEscape(conversation.ID)+"/activities",
+		Activity{Type: ActivityTypeMessage, Text: text},
+		nil,
+	)
+}
+
+// accessToken returns the bot's access token, requesting a new one once the current token expires.
+func (connector *connector) accessToken() (string, error) {
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	connector.mutex.Lock()
+	defer connector.mutex.Unlock()
+
+	if connector.token != "" && time.Now().Before(connector.expires) {
+		return connector.token, nil
+	}
+
+	resp, err := connector.httpClient.PostForm(connector.tokenURL, url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {connector.appID},
+		"client_secret": {connector.appPassword},
+		"scope":         {connectorScope},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("The Teams bot's access token was refused with status %v", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+
+	connector.token = response.AccessToken
+	connector.expires = time.Now().Add(time.Duration(response.ExpiresIn)*time.Second - tokenRefreshMargin)
+
+	return connector.token, nil
+}
+
+// post sends the payload to the connector, decoding the response into result when one is provided.
+func (connector *connector) post(route string, payload, result interface{}) error {
+	token, err := connector.accessToken()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", route, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := connector.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("The Teams connector responded with status %v", resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
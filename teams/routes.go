@@ -0,0 +1,44 @@
+package teams
+
+import (
+	"github.com/hamologist/rps/chat"
+	"github.com/hamologist/rps/config"
+	"github.com/hamologist/rps/server"
+)
+
+const (
+	// HandleMessageRoute defines the route used for the outgoing webhook's callback URL.
+	HandleMessageRoute = "/teams/rps"
+)
+
+// RegisterRoutes registers the Teams routes on the GameServer's ServeMux.
+// Requests are verified using the outgoing webhook's security token, and player links are built from the public URL.
+// Results are posted to the configured incoming webhook for the same channel, if any.
+func RegisterRoutes(gameServer *server.GameServer, cfg config.Config) error {
+	secret, err := cfg.Teams.SecretBytes()
+	if err != nil {
+		return err
+	}
+
+	connector := newConnector(TokenURL, cfg.Teams.AppID, cfg.Teams.AppPassword)
+	registerRoutes(gameServer, newAdapter(gameServer, secret, cfg.PublicURL, cfg.Teams.IncomingWebhook, connector))
+
+	return nil
+}
+
+func registerRoutes(gameServer *server.GameServer, adapter *adapter) {
+	service := chat.NewService(gameServer, adapter)
+
+	gameServer.ServeMux.HandleFunc(HandleMessageRoute, service.HandleCommand)
+}
+
+func newAdapter(gameServer *server.GameServer, secret []byte, publicURL, webhookURL string, connector *connector) *adapter {
+	return &adapter{
+		gameServer:  gameServer,
+		secret:      secret,
+		publicURL:   publicURL,
+		webhookURL:  webhookURL,
+		connector:   connector,
+		serviceURLs: make(map[string]string),
+	}
+}